@@ -1,95 +1,188 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"regexp"
-	"strings"
+	"os/signal"
+	"text/tabwriter"
 
 	"github.com/vektra/mockery/mockery"
 )
 
-const regexMetadataChars = "\\.+*?()|[]{}^$"
-
 type Config struct {
-	fName      string
-	fPrint     bool
-	fOutput    string
-	fDir       string
-	fRecursive bool
-	fAll       bool
-	fIP        bool
-	fTO        bool
-	fCase      string
-	fNote      string
+	fName            string
+	fPrint           bool
+	fOutput          string
+	fDir             string
+	fRecursive       bool
+	fAll             bool
+	fIP              bool
+	fTO              bool
+	fCase            string
+	fNote            string
+	fList            bool
+	fFormat          string
+	fCheck           bool
+	fForce           bool
+	fWatch           bool
+	fPrune           bool
+	fDryRun          bool
+	fMockName        string
+	fFileName        string
+	fOutPkg          string
+	fKeepTree        bool
+	fQuiet           bool
+	fVerbose         bool
+	fLogFormat       string
+	fWithExpecter    bool
+	fWithConstructor bool
+	fWithAssertion   bool
+	fUnrollVariadic  bool
+	fBackend         string
+	fTemplate        string
 }
 
 func main() {
 	config := parseConfigFromArgs(os.Args)
 
-	var recursive bool
-	var filter *regexp.Regexp
-	var err error
-	var limitOne bool
+	log := &mockery.Logger{Out: os.Stderr, Level: mockery.LogInfo, Format: config.fLogFormat}
+	if config.fQuiet {
+		log.Level = mockery.LogError
+	} else if config.fVerbose {
+		log.Level = mockery.LogDebug
+	}
 
-	if config.fName != "" && config.fAll {
-		fmt.Fprintln(os.Stderr, "Specify -name or -all, but not both")
+	if config.fQuiet && config.fVerbose {
+		fmt.Fprintln(os.Stderr, "Specify -quiet or -verbose, but not both")
 		os.Exit(1)
-	} else if config.fName != "" {
-		recursive = config.fRecursive
-		if strings.ContainsAny(config.fName, regexMetadataChars) {
-			if filter, err = regexp.Compile(config.fName); err != nil {
-				fmt.Fprintln(os.Stderr, "Invalid regular expression provided to -name")
-				os.Exit(1)
-			}
-		} else {
-			filter = regexp.MustCompile(fmt.Sprintf("^%s$", config.fName))
-			limitOne = true
-		}
-	} else if config.fAll {
-		recursive = true
-		filter = regexp.MustCompile(".*")
-	} else {
-		fmt.Fprintln(os.Stderr, "Use -name to specify the name of the interface or -all for all interfaces found")
+	} else if config.fLogFormat != mockery.LogFormatText && config.fLogFormat != mockery.LogFormatJSON {
+		fmt.Fprintln(os.Stderr, "Use -log-format text or -log-format json")
+		os.Exit(1)
+	}
+
+	opts := config.options()
+	opts.Log = log
+	if opts.Name == "" && !opts.All {
+		opts.GoGenerate = mockery.GoGenerateFromEnv()
+	}
+
+	ctx := context.Background()
+	if config.fWatch {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			cancel()
+		}()
+	}
+
+	result, err := mockery.Run(ctx, opts)
+	switch err {
+	case nil:
+	case mockery.ErrStale:
+		printStale(result.Missing, result.Outdated, result.Orphaned)
+		os.Exit(1)
+	case mockery.ErrInterfaceNotFound:
+		log.Error("Unable to find interface in any go files under this path", "name", config.fName, "dir", config.fDir)
+		os.Exit(1)
+	default:
+		log.Error(err.Error())
 		os.Exit(1)
 	}
 
-	var osp mockery.OutputStreamProvider
-	if config.fPrint {
-		osp = &mockery.StdoutStreamProvider{}
-	} else {
-		osp = &mockery.FileOutputStreamProvider{
-			BaseDir:   config.fOutput,
-			InPackage: config.fIP,
-			TestOnly:  config.fTO,
-			Case:      config.fCase,
+	if config.fList {
+		if err := printList(result.Interfaces, config.fFormat); err != nil {
+			log.Error("Unable to list interfaces", "error", err)
+			os.Exit(1)
 		}
 	}
+}
 
-	visitor := &mockery.GeneratorVisitor{
-		InPackage: config.fIP,
-		Note:      config.fNote,
-		Osp:       osp,
+// options maps the flags onto the options of mockery.Run.
+func (config *Config) options() mockery.Options {
+	return mockery.Options{
+		Name:            config.fName,
+		All:             config.fAll,
+		Dir:             config.fDir,
+		Recursive:       config.fRecursive,
+		Output:          config.fOutput,
+		Print:           config.fPrint,
+		InPackage:       config.fIP,
+		TestOnly:        config.fTO,
+		Case:            config.fCase,
+		Note:            config.fNote,
+		OutPackage:      config.fOutPkg,
+		KeepTree:        config.fKeepTree,
+		MockName:        config.fMockName,
+		FileName:        config.fFileName,
+		Force:           config.fForce,
+		WithExpecter:    config.fWithExpecter,
+		WithConstructor: config.fWithConstructor,
+		WithAssertion:   config.fWithAssertion,
+		UnrollVariadic:  config.fUnrollVariadic,
+		Backend:         config.fBackend,
+		Template:        config.fTemplate,
+		List:            config.fList,
+		Check:           config.fCheck,
+		Prune:           config.fPrune,
+		DryRun:          config.fDryRun,
+		Watch:           config.fWatch,
 	}
+}
 
-	walker := mockery.Walker{
-		BaseDir:   config.fDir,
-		Recursive: recursive,
-		Filter:    filter,
-		LimitOne:  limitOne,
+// printStale lists mocks that need regenerating.
+func printStale(missing, outdated, orphaned []string) {
+	for _, path := range missing {
+		fmt.Printf("missing: %s\n", path)
 	}
-	generated := walker.Walk(visitor)
+	for _, path := range outdated {
+		fmt.Printf("outdated: %s\n", path)
+	}
+	for _, path := range orphaned {
+		fmt.Printf("orphaned: %s\n", path)
+	}
+}
 
-	if config.fName != "" && !generated {
-		fmt.Printf("Unable to find %s in any go files under this path\n", config.fName)
-		os.Exit(1)
+func printList(ifaces []mockery.InterfaceInfo, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ifaces)
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', 0)
+		for _, iface := range ifaces {
+			output := iface.Output
+			if output == "" {
+				output = "-"
+			}
+			fmt.Fprintf(w, "%s.%s\t%s:%d\t%d methods\t%s\n", iface.Package, iface.Name, iface.File, iface.Line, iface.Methods, output)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown -format %q, use text or json", format)
 	}
 }
 
 func parseConfigFromArgs(args []string) Config {
 	config := Config{}
 
+	if len(args) > 1 {
+		switch args[1] {
+		case "list":
+			config.fList = true
+			args = append([]string{args[0]}, args[2:]...)
+		case "check":
+			config.fCheck = true
+			args = append([]string{args[0]}, args[2:]...)
+		}
+	}
+
 	flagSet := flag.NewFlagSet(args[0], flag.ExitOnError)
 
 	flagSet.StringVar(&config.fName, "name", "", "name or matching regular expression of interface to generate mock for")
@@ -100,8 +193,27 @@ func parseConfigFromArgs(args []string) Config {
 	flagSet.BoolVar(&config.fAll, "all", false, "generates mocks for all found interfaces in all sub-directories")
 	flagSet.BoolVar(&config.fIP, "inpkg", false, "generate a mock that goes inside the original package")
 	flagSet.BoolVar(&config.fTO, "testonly", false, "generate a mock in a _test.go file")
-	flagSet.StringVar(&config.fCase, "case", "camel", "name the mocked file using casing convention")
+	flagSet.StringVar(&config.fCase, "case", "camel", "name the mocked file using casing convention: camel, underscore, snake, kebab, lower or upper-first")
 	flagSet.StringVar(&config.fNote, "note", "", "comment to insert into prologue of each generated file")
+	flagSet.BoolVar(&config.fCheck, "check", config.fCheck, "compare generated mocks against existing files instead of writing them")
+	flagSet.BoolVar(&config.fForce, "force", false, "regenerate mocks even if their fingerprint is unchanged")
+	flagSet.BoolVar(&config.fWatch, "watch", false, "keep running and regenerate mocks when their interfaces change")
+	flagSet.BoolVar(&config.fPrune, "prune", false, "remove generated mocks whose interface no longer exists, requires -all")
+	flagSet.BoolVar(&config.fDryRun, "dry-run", false, "only list the mocks -prune would remove")
+	flagSet.StringVar(&config.fOutPkg, "outpkg", "", "package name of out-of-package mocks, defaults to the name of the output directory")
+	flagSet.BoolVar(&config.fKeepTree, "keeptree", false, "mirror the layout of the source packages under the output directory, one mocks package per source package")
+	flagSet.StringVar(&config.fMockName, "mockname", "", "template for the name of the mock type, e.g. Fake{{.InterfaceName}}")
+	flagSet.StringVar(&config.fFileName, "filename", "", "template for the file name of each mock, e.g. fake_{{.InterfaceName | snake}}.go")
+	flagSet.BoolVar(&config.fQuiet, "quiet", false, "only log errors")
+	flagSet.BoolVar(&config.fVerbose, "verbose", false, "also log debug messages such as package loads")
+	flagSet.StringVar(&config.fLogFormat, "log-format", "text", "format of the log written to stderr: text or json")
+	flagSet.BoolVar(&config.fWithExpecter, "with-expecter", false, "generate an EXPECT method with typed helpers to set up expectations")
+	flagSet.BoolVar(&config.fWithConstructor, "with-constructor", false, "generate a constructor that asserts the mock's expectations when the test finishes")
+	flagSet.BoolVar(&config.fWithAssertion, "with-assertion", false, "generate a compile-time check that each mock implements its interface")
+	flagSet.BoolVar(&config.fUnrollVariadic, "unroll-variadic", false, "pass the variadic arguments of a method to Called one by one instead of as a single slice")
+	flagSet.StringVar(&config.fBackend, "backend", "testify", "kind of code to generate: testify, gomock, funcfake, spy, stub, replay or decorator")
+	flagSet.StringVar(&config.fTemplate, "template", "", "text/template file generating the code for each interface instead of the backend")
+	flagSet.StringVar(&config.fFormat, "format", "text", "output format of the list subcommand: text or json")
 
 	flagSet.Parse(args[1:])
 
@@ -23,10 +23,29 @@ func TestParseConfigDefaults(t *testing.T) {
 	assert.Equal(t, false, config.fTO)
 	assert.Equal(t, "camel", config.fCase)
 	assert.Equal(t, "", config.fNote)
+	assert.Equal(t, false, config.fList)
+	assert.Equal(t, "text", config.fFormat)
+	assert.Equal(t, false, config.fForce)
+	assert.Equal(t, false, config.fWatch)
+	assert.Equal(t, false, config.fPrune)
+	assert.Equal(t, false, config.fDryRun)
+	assert.Equal(t, "", config.fMockName)
+	assert.Equal(t, "", config.fFileName)
+	assert.Equal(t, "", config.fOutPkg)
+	assert.Equal(t, false, config.fKeepTree)
+	assert.Equal(t, false, config.fQuiet)
+	assert.Equal(t, false, config.fVerbose)
+	assert.Equal(t, "text", config.fLogFormat)
+	assert.Equal(t, false, config.fWithExpecter)
+	assert.Equal(t, false, config.fWithConstructor)
+	assert.Equal(t, false, config.fWithAssertion)
+	assert.Equal(t, false, config.fUnrollVariadic)
+	assert.Equal(t, "testify", config.fBackend)
+	assert.Equal(t, "", config.fTemplate)
 }
 
 func TestParseConfigFlippingValues(t *testing.T) {
-	config := configFromCommandLine("mockery -name hi -print -output output -dir dir -recursive -all -inpkg -testonly -case case -note note")
+	config := configFromCommandLine("mockery -name hi -print -output output -dir dir -recursive -all -inpkg -testonly -case case -note note -force -watch -prune -dry-run -mockname mockname -filename filename -outpkg outpkg -keeptree -quiet -verbose -log-format json -with-expecter -with-constructor -with-assertion -unroll-variadic -backend gomock -template template.tmpl")
 	assert.Equal(t, "hi", config.fName)
 	assert.Equal(t, true, config.fPrint)
 	assert.Equal(t, "output", config.fOutput)
@@ -37,4 +56,34 @@ func TestParseConfigFlippingValues(t *testing.T) {
 	assert.Equal(t, true, config.fTO)
 	assert.Equal(t, "case", config.fCase)
 	assert.Equal(t, "note", config.fNote)
+	assert.Equal(t, true, config.fForce)
+	assert.Equal(t, true, config.fWatch)
+	assert.Equal(t, true, config.fPrune)
+	assert.Equal(t, true, config.fDryRun)
+	assert.Equal(t, "mockname", config.fMockName)
+	assert.Equal(t, "filename", config.fFileName)
+	assert.Equal(t, "outpkg", config.fOutPkg)
+	assert.Equal(t, true, config.fKeepTree)
+	assert.Equal(t, true, config.fQuiet)
+	assert.Equal(t, true, config.fVerbose)
+	assert.Equal(t, "json", config.fLogFormat)
+	assert.Equal(t, true, config.fWithExpecter)
+	assert.Equal(t, true, config.fWithConstructor)
+	assert.Equal(t, true, config.fWithAssertion)
+	assert.Equal(t, true, config.fUnrollVariadic)
+	assert.Equal(t, "gomock", config.fBackend)
+	assert.Equal(t, "template.tmpl", config.fTemplate)
+}
+
+func TestParseConfigListSubcommand(t *testing.T) {
+	config := configFromCommandLine("mockery list -name hi -format json")
+	assert.Equal(t, true, config.fList)
+	assert.Equal(t, "hi", config.fName)
+	assert.Equal(t, "json", config.fFormat)
+}
+
+func TestParseConfigCheck(t *testing.T) {
+	assert.Equal(t, true, configFromCommandLine("mockery check -all").fCheck)
+	assert.Equal(t, true, configFromCommandLine("mockery -all -check").fCheck)
+	assert.Equal(t, false, configFromCommandLine("mockery -all").fCheck)
 }
@@ -0,0 +1,97 @@
+package mockery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backends select the kind of code generated for each interface.
+const (
+	// BackendTestify generates mocks embedding testify's mock.Mock.
+	BackendTestify = "testify"
+	// BackendGomock generates mocks and recorders driven by a gomock
+	// Controller, named MockX as mockgen does.
+	BackendGomock = "gomock"
+	// BackendFuncFake generates fakes with a function field per method,
+	// depending on no mocking library.
+	BackendFuncFake = "funcfake"
+	// BackendSpy generates spies recording the arguments of every call and
+	// returning stubbed results, safe for concurrent use.
+	BackendSpy = "spy"
+	// BackendStub generates stubs whose methods do nothing but return zero
+	// values.
+	BackendStub = "stub"
+	// BackendReplay generates a recorder forwarding calls to an
+	// implementation and recording them, and a replayer answering calls from
+	// such a recording, both built on the replay package.
+	BackendReplay = "replay"
+	// BackendDecorator generates wrappers delegating to an implementation
+	// and calling hooks around each call, for logging or metrics.
+	BackendDecorator = "decorator"
+)
+
+var backends = []string{BackendTestify, BackendGomock, BackendFuncFake, BackendSpy, BackendStub, BackendReplay, BackendDecorator}
+
+// ValidateBackend returns an error if name is not one of the backends.
+func ValidateBackend(name string) error {
+	for _, known := range backends {
+		if name == known {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown backend %q, use one of %s", name, strings.Join(backends, ", "))
+}
+
+// reservedNames lists the identifiers the methods generated by each backend
+// refer to. Parameters with these names would shadow them, so they are
+// renamed.
+var reservedNames = map[string][]string{
+	BackendTestify:   {"append", "fmt", "len", "make", "panic"},
+	BackendGomock:    {"append", "reflect"},
+	BackendFuncFake:  {"panic"},
+	BackendSpy:       {"append", "len", "make"},
+	BackendDecorator: {"time"},
+}
+
+// reserved reports whether the generated methods refer to name. Code from a
+// Template refers to nothing mockery knows of.
+func (g *Generator) reserved(name string) bool {
+	if g.Template != nil {
+		return false
+	}
+
+	backend := g.Backend
+	if backend == "" {
+		backend = BackendTestify
+	}
+
+	for _, reserved := range reservedNames[backend] {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// generateBackendImports writes the imports the code of the backend needs.
+func (g *Generator) generateBackendImports() {
+	if g.Template != nil {
+		return
+	}
+
+	switch g.Backend {
+	case BackendGomock:
+		g.printf("import \"reflect\"\n")
+		g.printf("import \"github.com/golang/mock/gomock\"\n\n")
+	case BackendFuncFake, BackendStub:
+	case BackendSpy:
+		g.printf("import \"sync\"\n\n")
+	case BackendReplay:
+		g.printf("import \"github.com/vektra/mockery/replay\"\n\n")
+	case BackendDecorator:
+		g.printf("import \"time\"\n\n")
+	default:
+		g.printf("import \"github.com/stretchr/testify/mock\"\n\n")
+	}
+}
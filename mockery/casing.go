@@ -0,0 +1,132 @@
+package mockery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Casing styles accepted for the file names of mocks.
+const (
+	// CaseCamel keeps the interface name as it is.
+	CaseCamel = "camel"
+	// CaseUnderscore and CaseSnake lower case words separated by underscores:
+	// HTTPClient becomes http_client.
+	CaseUnderscore = "underscore"
+	CaseSnake      = "snake"
+	// CaseKebab lower cases words separated by dashes: http-client.
+	CaseKebab = "kebab"
+	// CaseLower lower cases the whole name: httpclient.
+	CaseLower = "lower"
+	// CaseUpperFirst upper cases the first letter: requester becomes Requester.
+	CaseUpperFirst = "upper-first"
+)
+
+var caseStyles = []string{CaseCamel, CaseUnderscore, CaseSnake, CaseKebab, CaseLower, CaseUpperFirst}
+
+// mixedCaseWords are kept as a single word even though their casing would
+// otherwise split them.
+var mixedCaseWords = []string{"GraphQL", "IPv4", "IPv6", "MySQL", "OAuth"}
+
+// ValidateCase returns an error if style is not one of the casing styles.
+func ValidateCase(style string) error {
+	for _, known := range caseStyles {
+		if style == known {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown case %q, use one of %s", style, strings.Join(caseStyles, ", "))
+}
+
+// convertCase returns name in the given casing style, which must have been
+// checked with ValidateCase.
+func convertCase(style, name string) string {
+	switch style {
+	case CaseUnderscore, CaseSnake:
+		return joinWords(name, "_")
+	case CaseKebab:
+		return joinWords(name, "-")
+	case CaseLower:
+		return strings.ToLower(name)
+	case CaseUpperFirst:
+		return upperFirst(name)
+	default:
+		return name
+	}
+}
+
+func joinWords(name, sep string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	return strings.Join(words, sep)
+}
+
+// splitWords splits an identifier into words. Runs of upper case letters are
+// kept together as acronyms, so HTTPServer is HTTP and Server, and digits stay
+// with the word they follow, so OAuth2Provider is OAuth2 and Provider.
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+
+	runes := []rune(name)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+
+		if known := mixedCaseWordAt(runes, i); known != "" {
+			flush()
+			word = append(word, []rune(known)...)
+			i += len(known) - 1
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if !unicode.IsUpper(prev) || nextIsLower {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// mixedCaseWordAt returns the mixed case word starting at runes[i], if it is
+// not immediately followed by more lower case letters.
+func mixedCaseWordAt(runes []rune, i int) string {
+	for _, known := range mixedCaseWords {
+		end := i + len(known)
+		if end > len(runes) || string(runes[i:end]) != known {
+			continue
+		}
+
+		if end < len(runes) && unicode.IsLower(runes[end]) {
+			continue
+		}
+
+		return known
+	}
+
+	return ""
+}
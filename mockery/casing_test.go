@@ -0,0 +1,37 @@
+package mockery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWords(t *testing.T) {
+	assert.Equal(t, []string{"Notify", "Event"}, splitWords("NotifyEvent"))
+	assert.Equal(t, []string{"HTTP", "Client"}, splitWords("HTTPClient"))
+	assert.Equal(t, []string{"OAuth2", "Provider"}, splitWords("OAuth2Provider"))
+	assert.Equal(t, []string{"HTTP2", "Server"}, splitWords("HTTP2Server"))
+	assert.Equal(t, []string{"S3", "Client"}, splitWords("S3Client"))
+	assert.Equal(t, []string{"IPv6", "Resolver"}, splitWords("IPv6Resolver"))
+	assert.Equal(t, []string{"get", "URL"}, splitWords("getURL"))
+	assert.Equal(t, []string{"Oauthish"}, splitWords("Oauthish"))
+	assert.Equal(t, []string{"already", "snake"}, splitWords("already_snake"))
+}
+
+func TestConvertCase(t *testing.T) {
+	assert.Equal(t, "HTTPClient", convertCase(CaseCamel, "HTTPClient"))
+	assert.Equal(t, "http_client", convertCase(CaseUnderscore, "HTTPClient"))
+	assert.Equal(t, "oauth2_provider", convertCase(CaseSnake, "OAuth2Provider"))
+	assert.Equal(t, "oauth2-provider", convertCase(CaseKebab, "OAuth2Provider"))
+	assert.Equal(t, "httpclient", convertCase(CaseLower, "HTTPClient"))
+	assert.Equal(t, "Requester", convertCase(CaseUpperFirst, "requester"))
+}
+
+func TestValidateCase(t *testing.T) {
+	for _, style := range caseStyles {
+		assert.NoError(t, ValidateCase(style))
+	}
+
+	assert.Error(t, ValidateCase("Underscore"))
+	assert.Error(t, ValidateCase(""))
+}
@@ -0,0 +1,150 @@
+package mockery
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CheckingOutputStreamProvider generates mocks into memory and compares them
+// against the files Files would write, without touching disk.
+type CheckingOutputStreamProvider struct {
+	Files *FileOutputStreamProvider
+
+	Missing  []string
+	Outdated []string
+}
+
+func (this *CheckingOutputStreamProvider) GetWriter(iface *Interface, pkg string) (io.Writer, error, Cleanup) {
+	path, err := this.Files.filePath(iface)
+	if err != nil {
+		return nil, err, func() error { return nil }
+	}
+	this.Files.markProduced(path)
+
+	var buf bytes.Buffer
+
+	return &buf, nil, func() error {
+		existing, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			this.Missing = append(this.Missing, path)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(existing, buf.Bytes()) {
+			this.Outdated = append(this.Outdated, path)
+		}
+		return nil
+	}
+}
+
+// Orphans returns the files carrying mockery's generated header that were not
+// produced by this run. It only makes sense after visiting every interface.
+func (this *CheckingOutputStreamProvider) Orphans() ([]string, error) {
+	return this.Files.orphans()
+}
+
+// orphans returns the generated files in the output directories that no
+// interface visited so far maps to.
+func (this *FileOutputStreamProvider) orphans() ([]string, error) {
+	return findOrphans(this.outputDirs(), !this.InPackage, this.produced)
+}
+
+// outputDirs returns the directories mocks are written to: BaseDir for
+// out-of-package mocks, or the package directories and those of the produced
+// files otherwise.
+func (this *FileOutputStreamProvider) outputDirs() []string {
+	if !this.InPackage {
+		return []string{this.BaseDir}
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	// Produced files have absolute paths, so the walk of a package directory
+	// must yield absolute paths too.
+	for _, dir := range this.PackageDirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			add(abs)
+		}
+	}
+	for path := range this.produced {
+		add(filepath.Dir(path))
+	}
+
+	sort.Strings(dirs)
+	return dirs
+}
+
+// findOrphans returns the mockery generated files in dirs, and their
+// sub-directories if recursive is set, that are not in produced. Files mockery
+// did not generate are never returned.
+func findOrphans(dirs []string, recursive bool, produced map[string]bool) ([]string, error) {
+	var orphans []string
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != dir && !recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if filepath.Ext(path) != ".go" || produced[path] {
+				return nil
+			}
+
+			generated, err := isGenerated(path)
+			if err != nil {
+				return err
+			}
+
+			if generated {
+				orphans = append(orphans, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return orphans, nil
+}
+
+// isGenerated reports whether the file at path starts with mockery's header.
+func isGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return strings.HasPrefix(line, generatedPrefix), nil
+}
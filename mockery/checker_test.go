@@ -0,0 +1,46 @@
+package mockery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckingOutputStreamProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	generated := generatedPrefix + " v" + SemVer + ". DO NOT EDIT.\n\npackage mocks\n"
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("Current.go", generated)
+	write("Outdated.go", generated+"// old\n")
+	write("Orphan.go", generated)
+	write("Handwritten.go", "package mocks\n")
+
+	checker := &CheckingOutputStreamProvider{
+		Files: &FileOutputStreamProvider{BaseDir: dir},
+	}
+
+	for _, name := range []string{"Current", "Outdated", "Missing"} {
+		w, err, cleanup := checker.GetWriter(&Interface{Name: name}, "mocks")
+		require.NoError(t, err)
+
+		w.Write([]byte(generated))
+		assert.NoError(t, cleanup())
+	}
+
+	assert.Equal(t, []string{filepath.Join(dir, "Missing.go")}, checker.Missing)
+	assert.Equal(t, []string{filepath.Join(dir, "Outdated.go")}, checker.Outdated)
+
+	orphans, err := checker.Orphans()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "Orphan.go")}, orphans)
+}
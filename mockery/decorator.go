@@ -0,0 +1,53 @@
+package mockery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateDecorator writes, for the mock name X, an XDecorator delegating each
+// call to an implementation of the interface and calling its Before and After
+// hooks, when set, around it.
+func (g *Generator) generateDecorator() {
+	decorator := g.mockName() + "Decorator"
+	methods := g.methods()
+
+	g.printf("// %s is a %s delegating to Inner and calling Before and After, when\n// set, around each call\n", decorator, g.iface.Name)
+	g.printf("type %s struct {\n", decorator)
+	g.printf("\tInner  %s\n", g.innerType(methods))
+	g.printf("\tBefore func(method string, args []interface{})\n")
+	g.printf("\tAfter  func(method string, results []interface{}, duration time.Duration)\n")
+	g.printf("}\n\n")
+
+	g.generateAssertion(decorator)
+
+	for i, m := range methods {
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		var results []string
+		for idx := range m.Returns.Types {
+			results = append(results, fmt.Sprintf("r%d", idx))
+		}
+
+		g.printf("// %s calls Inner.%s between the hooks\n", m.Name, m.Name)
+		g.printf("func (_d *%s) %s%s {\n", decorator, m.Name, m.signature())
+		g.printf("\tif _d.Before != nil {\n")
+		g.printf("\t\t_d.Before(%q, []interface{}{%s})\n", m.Name, strings.Join(m.Params.Names, ", "))
+		g.printf("\t}\n\n")
+		g.printf("\t_start := time.Now()\n")
+		if len(results) == 0 {
+			g.printf("\t_d.Inner.%s(%s)\n\n", m.Name, m.Params.callNames())
+		} else {
+			g.printf("\t%s := _d.Inner.%s(%s)\n\n", strings.Join(results, ", "), m.Name, m.Params.callNames())
+		}
+		g.printf("\tif _d.After != nil {\n")
+		g.printf("\t\t_d.After(%q, []interface{}{%s}, time.Since(_start))\n", m.Name, strings.Join(results, ", "))
+		g.printf("\t}\n")
+		if len(results) > 0 {
+			g.printf("\treturn %s\n", strings.Join(results, ", "))
+		}
+		g.printf("}\n")
+	}
+}
@@ -0,0 +1,84 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorDecorator(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendDecorator
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// RequesterDecorator is a Requester delegating to Inner and calling Before and After, when
+// set, around each call
+type RequesterDecorator struct {
+	Inner  Requester
+	Before func(method string, args []interface{})
+	After  func(method string, results []interface{}, duration time.Duration)
+}
+
+// Get calls Inner.Get between the hooks
+func (_d *RequesterDecorator) Get(path string) (string, error) {
+	if _d.Before != nil {
+		_d.Before("Get", []interface{}{path})
+	}
+
+	_start := time.Now()
+	r0, r1 := _d.Inner.Get(path)
+
+	if _d.After != nil {
+		_d.After("Get", []interface{}{r0, r1}, time.Since(_start))
+	}
+	return r0, r1
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorDecoratorVariadic(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendDecorator
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "\t\t_d.Before(\"Get\", []interface{}{values})\n")
+	assert.Contains(t, gen.buf.String(), "\tr0 := _d.Inner.Get(values...)\n")
+}
+
+func TestGeneratorDecoratorWhereArgumentNameShadowsGeneratedCode(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Sleeper")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendDecorator
+
+	assert.NoError(t, gen.Generate())
+	assert.Contains(t, gen.buf.String(), `func (_d *SleeperDecorator) Sleep(_a0 int) error {
+	if _d.Before != nil {
+		_d.Before("Sleep", []interface{}{_a0})
+	}
+
+	_start := time.Now()
+	r0 := _d.Inner.Sleep(_a0)`)
+}
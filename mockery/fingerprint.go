@@ -0,0 +1,75 @@
+package mockery
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fingerprintPrefix starts the header line recording a mock's fingerprint.
+const fingerprintPrefix = "// Fingerprint: "
+
+// Fingerprint returns a digest of the resolved method set of iface, the
+// version of mockery and the options the mock is generated with. Two mocks
+// with the same fingerprint are generated identically.
+func Fingerprint(iface *Interface, options ...string) string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "mockery %s\n", SemVer)
+	for _, opt := range options {
+		fmt.Fprintf(h, "option %s\n", opt)
+	}
+
+	// The source package is created from a file path, which changes across
+	// checkouts. Out-of-package mocks import it by its import path, so that
+	// is hashed instead and moving the package regenerates them.
+	var source string
+	if iface.Pkg != nil {
+		source = iface.Pkg.Name()
+	}
+	if path, err := importPath(iface); err == nil {
+		source = filepath.ToSlash(path)
+	}
+	qualifier := func(pkg *types.Package) string {
+		if pkg == iface.Pkg {
+			return source
+		}
+		return pkg.Path()
+	}
+
+	fmt.Fprintf(h, "package %s\n", source)
+	fmt.Fprintf(h, "interface %s\n", iface.Name)
+	for i := 0; i < iface.Type.NumMethods(); i++ {
+		fn := iface.Type.Method(i)
+		fmt.Fprintf(h, "method %s %s\n", fn.Name(), types.TypeString(fn.Type(), qualifier))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readFingerprint returns the fingerprint recorded in the header of a mock
+// generated by mockery, or an empty string if there is none.
+func readFingerprint(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), generatedPrefix) {
+		return ""
+	}
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), fingerprintPrefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(scanner.Text(), fingerprintPrefix)
+}
@@ -0,0 +1,64 @@
+package mockery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	fingerprint := Fingerprint(iface, "inpkg=false")
+
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, Fingerprint(iface, "inpkg=false"))
+	assert.NotEqual(t, fingerprint, Fingerprint(iface, "inpkg=true"))
+}
+
+func TestFingerprintSourcePackagePath(t *testing.T) {
+	defer os.Setenv("GOPATH", os.Getenv("GOPATH"))
+	os.Setenv("GOPATH", "/go")
+
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "custom_error.go")))
+
+	iface, err := parser.Find("KeyManager")
+	require.NoError(t, err)
+
+	iface.Path = "/go/src/example.com/a/custom_error.go"
+	fingerprint := Fingerprint(iface)
+
+	iface.Path = "/home/user/go/src/example.com/a/custom_error.go"
+	os.Setenv("GOPATH", "/home/user/go")
+	assert.Equal(t, fingerprint, Fingerprint(iface))
+
+	iface.Path = "/home/user/go/src/example.com/b/custom_error.go"
+	assert.NotEqual(t, fingerprint, Fingerprint(iface))
+}
+
+func TestReadFingerprint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gen := NewGenerator(nil, pkg)
+	gen.GenerateHeader("abc123")
+
+	path := filepath.Join(dir, "Requester.go")
+	require.NoError(t, ioutil.WriteFile(path, gen.buf.Bytes(), 0644))
+	assert.Equal(t, "abc123", readFingerprint(path))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("// Fingerprint: abc123\n"), 0644))
+	assert.Equal(t, "", readFingerprint(path))
+
+	assert.Equal(t, "", readFingerprint(filepath.Join(dir, "Missing.go")))
+}
@@ -0,0 +1,11 @@
+package test
+
+import "io"
+
+type GoGenerateFirst interface {
+	Read() io.Reader
+}
+
+type GoGenerateSecond interface {
+	Write(w io.Writer) error
+}
@@ -0,0 +1,7 @@
+package test
+
+import "context"
+
+type RequesterContext interface {
+	Get(ctx context.Context, path string, progress func(int), done chan<- bool) (string, error)
+}
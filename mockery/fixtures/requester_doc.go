@@ -0,0 +1,10 @@
+package test
+
+import "io"
+
+// RequesterDoc fetches documents.
+type RequesterDoc interface {
+	// Get returns the document at path.
+	Get(path string) (io.Reader, error)
+	Close()
+}
@@ -0,0 +1,14 @@
+package test
+
+type Buffer interface {
+	Truncate(len int) (int, error)
+	Format(fmt string, args ...interface{}) string
+}
+
+type Inspector interface {
+	Inspect(reflect bool) string
+}
+
+type Sleeper interface {
+	Sleep(time int) error
+}
@@ -0,0 +1,39 @@
+package mockery
+
+import "strings"
+
+// generateFuncFake writes a fake whose methods call the function field named
+// after them, XFunc for the method X, and panic when it is not set.
+func (g *Generator) generateFuncFake() {
+	fake := g.mockName()
+
+	var fields []string
+	methods := g.methods()
+	for _, m := range methods {
+		fields = append(fields, "\t"+m.Name+"Func func"+m.signature()+"\n")
+	}
+
+	g.printf("// %s is a fake of the %s interface whose methods call the function\n// fields named after them\n", fake, g.iface.Name)
+	g.printf("type %s struct {\n%s}\n\n", fake, strings.Join(fields, ""))
+
+	g.generateAssertion(g.mockName())
+
+	for i, m := range methods {
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		g.printf("// %s calls %sFunc\n", m.Name, m.Name)
+		g.printf("func (_f *%s) %s%s {\n", fake, m.Name, m.signature())
+		g.printf("\tif _f.%sFunc == nil {\n", m.Name)
+		g.printf("\t\tpanic(%q)\n", fake+"."+m.Name+" called but "+m.Name+"Func is not set")
+		g.printf("\t}\n")
+
+		if len(m.Returns.Types) == 0 {
+			g.printf("\t_f.%sFunc(%s)\n", m.Name, m.Params.callNames())
+		} else {
+			g.printf("\treturn _f.%sFunc(%s)\n", m.Name, m.Params.callNames())
+		}
+		g.printf("}\n")
+	}
+}
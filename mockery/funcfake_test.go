@@ -0,0 +1,55 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorFuncFake(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendFuncFake
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// Requester is a fake of the Requester interface whose methods call the function
+// fields named after them
+type Requester struct {
+	GetFunc func(path string) (string, error)
+}
+
+// Get calls GetFunc
+func (_f *Requester) Get(path string) (string, error) {
+	if _f.GetFunc == nil {
+		panic("Requester.Get called but GetFunc is not set")
+	}
+	return _f.GetFunc(path)
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorFuncFakeVariadic(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendFuncFake
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "\tGetFunc func(values ...string) bool\n")
+	assert.Contains(t, gen.buf.String(), "\treturn _f.GetFunc(values...)\n")
+}
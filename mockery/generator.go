@@ -8,7 +8,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"text/template"
 	"unicode"
 
 	"golang.org/x/tools/imports"
@@ -22,6 +24,31 @@ type Generator struct {
 	ip    bool
 	iface *Interface
 	pkg   string
+	// name is the name MockNameTemplate gave the mock, set by Generate.
+	name string
+
+	// MockNameTemplate, when set, names the mock type instead of the
+	// interface name and the Mock prefix of in-package mocks.
+	MockNameTemplate *template.Template
+	// WithExpecter adds an EXPECT method returning typed helpers to set up
+	// the expectations of each method.
+	WithExpecter bool
+	// WithConstructor adds a constructor registering the mock with a test,
+	// which asserts the mock's expectations when the test finishes.
+	WithConstructor bool
+	// WithAssertion adds a declaration that fails to compile once the mock
+	// no longer implements its interface.
+	WithAssertion bool
+	// UnrollVariadic passes the variadic arguments of a method to Called one
+	// by one instead of as a single slice, so expectations list them
+	// individually.
+	UnrollVariadic bool
+	// Backend selects the kind of mock generated, BackendTestify if empty.
+	// The expecter, constructor and variadic options only apply to testify.
+	Backend string
+	// Template, if set, generates the code for the interface in place of the
+	// backend, see TemplateData.
+	Template *template.Template
 }
 
 func NewGenerator(iface *Interface, pkg string) *Generator {
@@ -31,12 +58,26 @@ func NewGenerator(iface *Interface, pkg string) *Generator {
 	}
 }
 
+// generatedPrefix starts the first line of every file mockery generates.
+const generatedPrefix = "// Code generated by mockery"
+
+// GenerateHeader marks the output as generated by mockery, both for tools that
+// follow the Go convention and for mockery itself when looking for stale mocks.
+// A non-empty fingerprint is recorded so unchanged mocks can be skipped.
+func (g *Generator) GenerateHeader(fingerprint string) {
+	g.printf("%s v%s. DO NOT EDIT.\n", generatedPrefix, SemVer)
+	if fingerprint != "" {
+		g.printf("%s%s\n", fingerprintPrefix, fingerprint)
+	}
+	g.printf("\n")
+}
+
 func (g *Generator) GenerateIPPrologue() {
 	g.ip = true
 
 	g.printf("package %s\n\n", g.iface.File.Name)
 
-	g.printf("import \"github.com/stretchr/testify/mock\"\n\n")
+	g.generateBackendImports()
 	if g.iface.File.Imports == nil {
 		return
 	}
@@ -53,7 +94,11 @@ func (g *Generator) GenerateIPPrologue() {
 }
 
 func (g *Generator) mockName() string {
-	if g.ip {
+	if g.MockNameTemplate != nil {
+		return g.name
+	}
+
+	if g.ip || g.Backend == BackendGomock {
 		if ast.IsExported(g.iface.Name) {
 			return "Mock" + g.iface.Name
 		} else {
@@ -71,19 +116,34 @@ func (g *Generator) mockName() string {
 	return g.iface.Name
 }
 
+// ifaceRef returns how the mock's package refers to the mocked interface, or ""
+// when it cannot: interfaces in main or unexported ones can only be referred
+// to from their own package.
+func (g *Generator) ifaceRef() string {
+	if g.ip {
+		return g.iface.Name
+	}
+
+	if g.iface.Pkg == nil || g.iface.Pkg.Name() == "main" || !ast.IsExported(g.iface.Name) {
+		return ""
+	} else if g.iface.Pkg.Name() == g.pkg {
+		return g.iface.Name
+	}
+
+	return g.iface.Pkg.Name() + "." + g.iface.Name
+}
+
 func (g *Generator) GeneratePrologue(pkg string) {
 	g.printf("package %v\n\n", pkg)
 
-	goPath := os.Getenv("GOPATH")
-
-	local, err := filepath.Rel(filepath.Join(goPath, "src"), filepath.Dir(g.iface.Path))
+	local, err := importPath(g.iface)
 	if err != nil {
 		panic("unable to figure out path for package")
 	}
 
 	g.printf("import \"%s\"\n", local)
 
-	g.printf("import \"github.com/stretchr/testify/mock\"\n\n")
+	g.generateBackendImports()
 	if g.iface.File.Imports == nil {
 		return
 	}
@@ -99,6 +159,14 @@ func (g *Generator) GeneratePrologue(pkg string) {
 	g.printf("\n")
 }
 
+// importPath returns the import path of the package declaring iface, relative
+// to $GOPATH/src.
+func importPath(iface *Interface) (string, error) {
+	goPath := os.Getenv("GOPATH")
+
+	return filepath.Rel(filepath.Join(goPath, "src"), filepath.Dir(iface.Path))
+}
+
 func (g *Generator) GeneratePrologueNote(note string) {
 	if note != "" {
 		g.printf("\n")
@@ -278,8 +346,12 @@ func (g *Generator) genList(list *types.Tuple, varadic bool) *paramList {
 
 		pname := v.Name()
 
-		if pname == g.pkg {
-			// Argument is same as our package name
+		if pname == g.pkg || g.reserved(pname) {
+			// Argument is same as our package name or an identifier the
+			// generated code uses
+			pname = ""
+		} else if !g.ip && g.iface.Pkg != nil && pname == g.iface.Pkg.Name() {
+			// Argument is same as the mocked package, which is imported
 			pname = ""
 		} else if g.iface.Pkg != nil {
 			for _, imp := range g.iface.Pkg.Imports() {
@@ -304,6 +376,59 @@ func (g *Generator) genList(list *types.Tuple, varadic bool) *paramList {
 	return &params
 }
 
+// callNames returns the names of the parameters as arguments of a call,
+// spreading the variadic one.
+func (p *paramList) callNames() string {
+	var names []string
+	for i, name := range p.Names {
+		if strings.HasPrefix(p.Types[i], "...") {
+			name += "..."
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// method is a method of the mocked interface, its parameters and results
+// rendered for the mock's package.
+type method struct {
+	Name     string
+	Params   *paramList
+	Returns  *paramList
+	Variadic bool
+}
+
+func (g *Generator) methods() []*method {
+	var methods []*method
+	for i := 0; i < g.iface.Type.NumMethods(); i++ {
+		fn := g.iface.Type.Method(i)
+		ftype := fn.Type().(*types.Signature)
+
+		methods = append(methods, &method{
+			Name:     fn.Name(),
+			Params:   g.genList(ftype.Params(), ftype.Variadic()),
+			Returns:  g.genList(ftype.Results(), false),
+			Variadic: ftype.Variadic(),
+		})
+	}
+	return methods
+}
+
+// signature returns the parameters and results of m as written after the
+// method name in a declaration.
+func (m *method) signature() string {
+	params := "(" + strings.Join(m.Params.Params, ", ") + ")"
+
+	switch len(m.Returns.Types) {
+	case 0:
+		return params
+	case 1:
+		return params + " " + m.Returns.Types[0]
+	default:
+		return params + " (" + strings.Join(m.Returns.Types, ", ") + ")"
+	}
+}
+
 var ErrNotSetup = errors.New("not setup")
 
 func (g *Generator) Generate() error {
@@ -311,9 +436,56 @@ func (g *Generator) Generate() error {
 		return ErrNotSetup
 	}
 
+	if g.MockNameTemplate != nil {
+		name, err := executeNameTemplate(g.MockNameTemplate, g.iface, g.ip)
+		if err != nil {
+			return err
+		}
+		g.name = name
+	}
+
+	if g.Template != nil {
+		return g.generateTemplate()
+	}
+
+	switch g.Backend {
+	case BackendGomock:
+		g.generateGomock()
+	case BackendFuncFake:
+		g.generateFuncFake()
+	case BackendSpy:
+		g.generateSpy()
+	case BackendStub:
+		g.generateStub()
+	case BackendReplay:
+		g.generateReplay()
+	case BackendDecorator:
+		g.generateDecorator()
+	default:
+		g.generateTestify()
+	}
+
+	return nil
+}
+
+// generateAssertion writes a declaration that only compiles while typ
+// implements the interface, if asked for and possible.
+func (g *Generator) generateAssertion(typ string) {
+	if ref := g.ifaceRef(); g.WithAssertion && ref != "" {
+		g.printf("var _ %s = (*%s)(nil)\n\n", ref, typ)
+	}
+}
+
+func (g *Generator) generateTestify() {
 	g.printf("// %s is an autogenerated mock type for the %s type\n", g.mockName(), g.iface.Name)
 	g.printf("type %s struct {\n\tmock.Mock\n}\n\n", g.mockName())
 
+	g.generateAssertion(g.mockName())
+
+	if g.WithExpecter {
+		g.generateExpecter()
+	}
+
 	for i := 0; i < g.iface.Type.NumMethods(); i++ {
 		fn := g.iface.Type.Method(i)
 
@@ -335,25 +507,31 @@ func (g *Generator) Generate() error {
 			g.printf("(%s) {\n", strings.Join(returns.Types, ", "))
 		}
 
-		formatParamNames := func() string {
-			names := ""
-			for i, name := range params.Names {
-				if i > 0 {
-					names += ", "
-				}
-
-				paramType := params.Types[i]
-				// for variable args, move the ... to the end.
-				if strings.Index(paramType, "...") == 0 {
-					name += "..."
-				}
-				names += name
-			}
-			return names
+		called := strings.Join(params.Names, ", ")
+		if g.UnrollVariadic && ftype.Variadic() {
+			called = g.unrollVariadic(params)
 		}
 
 		if len(returns.Types) > 0 {
-			g.printf("\tret := _m.Called(%s)\n\n", strings.Join(params.Names, ", "))
+			g.printf("\tret := _m.Called(%s)\n\n", called)
+
+			method := g.mockName() + "." + fname
+
+			g.printf("\tif len(ret) == 0 {\n")
+			g.printf("\t\tpanic(%q)\n", "no return value specified for "+method)
+			g.printf("\t}\n\n")
+
+			if len(returns.Types) > 1 {
+				g.printf("\tif rf, ok := ret.Get(0).(func(%s) (%s)); ok {\n",
+					strings.Join(params.Types, ", "), strings.Join(returns.Types, ", "))
+				g.printf("\t\treturn rf(%s)\n", params.callNames())
+				g.printf("\t}\n\n")
+
+				g.printf("\tif len(ret) < %d {\n", len(returns.Types))
+				g.printf("\t\tpanic(fmt.Sprintf(%q, len(ret)))\n",
+					fmt.Sprintf("%s expects %d return values, got %%d", method, len(returns.Types)))
+				g.printf("\t}\n\n")
+			}
 
 			var (
 				ret []string
@@ -363,17 +541,16 @@ func (g *Generator) Generate() error {
 				g.printf("\tvar r%d %s\n", idx, typ)
 				g.printf("\tif rf, ok := ret.Get(%d).(func(%s) %s); ok {\n",
 					idx, strings.Join(params.Types, ", "), typ)
-				g.printf("\t\tr%d = rf(%s)\n", idx, formatParamNames())
-				g.printf("\t} else {\n")
-				if typ == "error" {
-					g.printf("\t\tr%d = ret.Error(%d)\n", idx, idx)
-				} else if returns.Nilable[idx] {
-					g.printf("\t\tif ret.Get(%d) != nil {\n", idx)
-					g.printf("\t\t\tr%d = ret.Get(%d).(%s)\n", idx, idx, typ)
-					g.printf("\t\t}\n")
+				g.printf("\t\tr%d = rf(%s)\n", idx, params.callNames())
+				if returns.Nilable[idx] {
+					g.printf("\t} else if rv, ok := ret.Get(%d).(%s); ok || ret.Get(%d) == nil {\n", idx, typ, idx)
 				} else {
-					g.printf("\t\tr%d = ret.Get(%d).(%s)\n", idx, idx, typ)
+					g.printf("\t} else if rv, ok := ret.Get(%d).(%s); ok {\n", idx, typ)
 				}
+				g.printf("\t\tr%d = rv\n", idx)
+				g.printf("\t} else {\n")
+				g.printf("\t\tpanic(fmt.Sprintf(%q, ret.Get(%d)))\n",
+					fmt.Sprintf("%s return value %d should be %s, got %%#v", method, idx, typ), idx)
 				g.printf("\t}\n\n")
 
 				ret = append(ret, fmt.Sprintf("r%d", idx))
@@ -381,13 +558,164 @@ func (g *Generator) Generate() error {
 
 			g.printf("\treturn %s\n", strings.Join(ret, ", "))
 		} else {
-			g.printf("\t_m.Called(%s)\n", strings.Join(params.Names, ", "))
+			g.printf("\t_m.Called(%s)\n", called)
 		}
 
 		g.printf("}\n")
+
+		if g.WithExpecter {
+			g.generateExpecterCall(fname, params, returns, g.UnrollVariadic && ftype.Variadic())
+		}
 	}
 
-	return nil
+	if g.WithConstructor {
+		g.generateConstructor()
+	}
+}
+
+// constructorName returns NewX for the mock X, or newX when X is unexported.
+func (g *Generator) constructorName() string {
+	if ast.IsExported(g.mockName()) {
+		return "New" + g.mockName()
+	}
+	return "new" + upperFirst(g.mockName())
+}
+
+func (g *Generator) generateConstructor() {
+	g.printf("\n// %s creates a %s registered with t, whose expectations are asserted\n// when t finishes\n", g.constructorName(), g.mockName())
+	g.printf("func %s(t interface {\n\tmock.TestingT\n\tCleanup(func())\n}) *%s {\n", g.constructorName(), g.mockName())
+	g.printf("\tm := &%s{}\n", g.mockName())
+	g.printf("\tm.Mock.Test(t)\n\n")
+	g.printf("\tt.Cleanup(func() { m.AssertExpectations(t) })\n\n")
+	g.printf("\treturn m\n")
+	g.printf("}\n")
+}
+
+// unrollVariadic writes the statements collecting the arguments of a variadic
+// method into a single slice and returns the arguments to pass to Called.
+func (g *Generator) unrollVariadic(params *paramList) string {
+	last := len(params.Names) - 1
+	variadic := params.Names[last]
+
+	g.printf("\t_va := make([]interface{}, len(%s))\n", variadic)
+	g.printf("\tfor _i := range %s {\n", variadic)
+	g.printf("\t\t_va[_i] = %s[_i]\n", variadic)
+	g.printf("\t}\n")
+	g.printf("\tvar _ca []interface{}\n")
+	if last > 0 {
+		g.printf("\t_ca = append(_ca, %s)\n", strings.Join(params.Names[:last], ", "))
+	}
+	g.printf("\t_ca = append(_ca, _va...)\n\n")
+
+	return "_ca..."
+}
+
+func (g *Generator) expecterName() string {
+	return g.mockName() + "_Expecter"
+}
+
+func (g *Generator) generateExpecter() {
+	g.printf("// %s sets up typed expectations on %s\n", g.expecterName(), g.mockName())
+	g.printf("type %s struct {\n\tmock *mock.Mock\n}\n\n", g.expecterName())
+	g.printf("func (_m *%s) EXPECT() *%s {\n", g.mockName(), g.expecterName())
+	g.printf("\treturn &%s{mock: &_m.Mock}\n", g.expecterName())
+	g.printf("}\n\n")
+}
+
+// generateExpecterCall writes the helper setting up an expectation of fname
+// and the call type whose Run and Return methods take the method's own types.
+// When unrolled, the variadic arguments are expected one by one, as the mock
+// passes them to Called.
+func (g *Generator) generateExpecterCall(fname string, params, returns *paramList, unrolled bool) {
+	call := fmt.Sprintf("%s_%s_Call", g.mockName(), fname)
+
+	g.printf("\n// %s is a *mock.Call with Run and Return methods typed after %s\n", call, fname)
+	g.printf("type %s struct {\n\t*mock.Call\n}\n\n", call)
+
+	var matchers, argTypes, runArgs []string
+	for i, name := range params.Names {
+		typ, spread := params.Types[i], ""
+		if strings.HasPrefix(typ, "...") {
+			typ, spread = "[]"+typ[3:], "..."
+		}
+
+		if unrolled && spread != "" {
+			matchers = append(matchers, name+" ...interface{}")
+		} else {
+			matchers = append(matchers, name+" interface{}")
+		}
+		argTypes = append(argTypes, typ)
+		runArgs = append(runArgs, fmt.Sprintf("_a%d%s", i, spread))
+	}
+
+	onArgs := strings.Join(append([]string{strconv.Quote(fname)}, params.Names...), ", ")
+	if unrolled {
+		last := len(params.Names) - 1
+		onArgs = fmt.Sprintf("%q, append([]interface{}{%s}, %s...)...", fname, strings.Join(params.Names[:last], ", "), params.Names[last])
+	}
+
+	g.printf("// %s sets up an expected call of %s, each argument being a value or a\n// matcher such as mock.Anything\n", fname, fname)
+	g.printf("func (_e *%s) %s(%s) *%s {\n", g.expecterName(), fname, strings.Join(matchers, ", "), call)
+	g.printf("\treturn &%s{Call: _e.mock.On(%s)}\n", call, onArgs)
+	g.printf("}\n\n")
+
+	g.printf("func (_c *%s) Run(run func(%s)) *%s {\n", call, strings.Join(params.Params, ", "), call)
+	g.printf("\t_c.Call.Run(func(args mock.Arguments) {\n")
+	for i, typ := range argTypes {
+		if unrolled && i == len(argTypes)-1 {
+			rest, count := "args", "len(args)"
+			if i > 0 {
+				rest, count = fmt.Sprintf("args[%d:]", i), fmt.Sprintf("len(args)-%d", i)
+			}
+			g.printf("\t\t_a%d := make(%s, %s)\n", i, typ, count)
+			g.printf("\t\tfor _i, _a := range %s {\n", rest)
+			g.printf("\t\t\tif _a != nil {\n")
+			g.printf("\t\t\t\t_a%d[_i] = _a.(%s)\n", i, typ[2:])
+			g.printf("\t\t\t}\n")
+			g.printf("\t\t}\n")
+		} else if params.Nilable[i] {
+			g.printf("\t\tvar _a%d %s\n", i, typ)
+			g.printf("\t\tif args[%d] != nil {\n", i)
+			g.printf("\t\t\t_a%d = args[%d].(%s)\n", i, i, typ)
+			g.printf("\t\t}\n")
+		} else {
+			g.printf("\t\t_a%d := args[%d].(%s)\n", i, i, typ)
+		}
+	}
+	g.printf("\t\trun(%s)\n", strings.Join(runArgs, ", "))
+	g.printf("\t})\n")
+	g.printf("\treturn _c\n")
+	g.printf("}\n\n")
+
+	var results, resultNames []string
+	for i, typ := range returns.Types {
+		results = append(results, fmt.Sprintf("_r%d %s", i, typ))
+		resultNames = append(resultNames, fmt.Sprintf("_r%d", i))
+	}
+
+	g.printf("func (_c *%s) Return(%s) *%s {\n", call, strings.Join(results, ", "), call)
+	g.printf("\t_c.Call.Return(%s)\n", strings.Join(resultNames, ", "))
+	g.printf("\treturn _c\n")
+	g.printf("}\n\n")
+
+	var runType string
+	switch len(returns.Types) {
+	case 0:
+		runType = fmt.Sprintf("func(%s)", strings.Join(params.Types, ", "))
+	case 1:
+		runType = fmt.Sprintf("func(%s) %s", strings.Join(params.Types, ", "), returns.Types[0])
+	default:
+		runType = fmt.Sprintf("func(%s) (%s)", strings.Join(params.Types, ", "), strings.Join(returns.Types, ", "))
+	}
+
+	g.printf("func (_c *%s) RunAndReturn(run %s) *%s {\n", call, runType, call)
+	if len(returns.Types) == 0 {
+		g.printf("\treturn _c.Run(run)\n")
+	} else {
+		g.printf("\t_c.Call.Return(run)\n")
+		g.printf("\treturn _c\n")
+	}
+	g.printf("}\n")
 }
 
 func (g *Generator) Write(w io.Writer) error {
@@ -32,18 +32,34 @@ type Requester struct {
 func (_m *Requester) Get(path string) (string, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Requester.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("Requester.Get expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 string
 	if rf, ok := ret.Get(0).(func(string) string); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(string); ok {
+		r0 = rv
 	} else {
-		r0 = ret.Get(0).(string)
+		panic(fmt.Sprintf("Requester.Get return value 0 should be string, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("Requester.Get return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -73,11 +89,17 @@ type Requester2 struct {
 func (_m *Requester2) Get(path string) error {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Requester2.Get")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string) error); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("Requester2.Get return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -107,11 +129,17 @@ type Requester3 struct {
 func (_m *Requester3) Get() error {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for Requester3.Get")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func() error); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("Requester3.Get return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -172,6 +200,44 @@ func (_m *mockRequester) Get() {
 	assert.Equal(t, expected, gen.buf.String())
 }
 
+func TestGeneratorHeader(t *testing.T) {
+	gen := NewGenerator(nil, pkg)
+
+	gen.GenerateHeader("")
+
+	expected := "// Code generated by mockery v" + SemVer + ". DO NOT EDIT.\n\n"
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorHeaderWithFingerprint(t *testing.T) {
+	gen := NewGenerator(nil, pkg)
+
+	gen.GenerateHeader("abc123")
+
+	expected := "// Code generated by mockery v" + SemVer + ". DO NOT EDIT.\n// Fingerprint: abc123\n\n"
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorMockNameTemplate(t *testing.T) {
+	parser := NewParser()
+	parser.Parse(testFile)
+
+	iface, err := parser.Find("Requester")
+	assert.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.MockNameTemplate, err = ParseNameTemplate("mockname", "Fake{{.InterfaceName}}")
+	require.NoError(t, err)
+
+	err = gen.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, gen.buf.String(), "// FakeRequester is an autogenerated mock type for the Requester type\ntype FakeRequester struct {")
+	assert.Contains(t, gen.buf.String(), "func (_m *FakeRequester) Get(path string) (string, error) {")
+}
+
 func TestGeneratorPrologue(t *testing.T) {
 	parser := NewParser()
 	parser.Parse(testFile)
@@ -266,13 +332,17 @@ type RequesterIface struct {
 func (_m *RequesterIface) Get() io.Reader {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterIface.Get")
+	}
+
 	var r0 io.Reader
 	if rf, ok := ret.Get(0).(func() io.Reader); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(io.Reader); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(io.Reader)
-		}
+		panic(fmt.Sprintf("RequesterIface.Get return value 0 should be io.Reader, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -303,20 +373,34 @@ type RequesterPtr struct {
 func (_m *RequesterPtr) Get(path string) (*string, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterPtr.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) (*string, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("RequesterPtr.Get expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 *string
 	if rf, ok := ret.Get(0).(func(string) *string); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(*string); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*string)
-		}
+		panic(fmt.Sprintf("RequesterPtr.Get return value 0 should be *string, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("RequesterPtr.Get return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -347,20 +431,34 @@ type RequesterSlice struct {
 func (_m *RequesterSlice) Get(path string) ([]string, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterSlice.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("RequesterSlice.Get expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 []string
 	if rf, ok := ret.Get(0).(func(string) []string); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).([]string); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		panic(fmt.Sprintf("RequesterSlice.Get return value 0 should be []string, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("RequesterSlice.Get return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -391,20 +489,34 @@ type RequesterArray struct {
 func (_m *RequesterArray) Get(path string) ([2]string, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterArray.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) ([2]string, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("RequesterArray.Get expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 [2]string
 	if rf, ok := ret.Get(0).(func(string) [2]string); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).([2]string); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([2]string)
-		}
+		panic(fmt.Sprintf("RequesterArray.Get return value 0 should be [2]string, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("RequesterArray.Get return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -436,18 +548,34 @@ type RequesterNS struct {
 func (_m *RequesterNS) Get(path string) (http.Response, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterNS.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) (http.Response, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("RequesterNS.Get expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 http.Response
 	if rf, ok := ret.Get(0).(func(string) http.Response); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(http.Response); ok {
+		r0 = rv
 	} else {
-		r0 = ret.Get(0).(http.Response)
+		panic(fmt.Sprintf("RequesterNS.Get return value 0 should be http.Response, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("RequesterNS.Get return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -479,13 +607,17 @@ type RequesterArgSameAsImport struct {
 func (_m *RequesterArgSameAsImport) Get(_a0 string) *json.RawMessage {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterArgSameAsImport.Get")
+	}
+
 	var r0 *json.RawMessage
 	if rf, ok := ret.Get(0).(func(string) *json.RawMessage); ok {
 		r0 = rf(_a0)
+	} else if rv, ok := ret.Get(0).(*json.RawMessage); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*json.RawMessage)
-		}
+		panic(fmt.Sprintf("RequesterArgSameAsImport.Get return value 0 should be *json.RawMessage, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -517,13 +649,17 @@ type RequesterArgSameAsNamedImport struct {
 func (_m *RequesterArgSameAsNamedImport) Get(_a0 string) *json.RawMessage {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterArgSameAsNamedImport.Get")
+	}
+
 	var r0 *json.RawMessage
 	if rf, ok := ret.Get(0).(func(string) *json.RawMessage); ok {
 		r0 = rf(_a0)
+	} else if rv, ok := ret.Get(0).(*json.RawMessage); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*json.RawMessage)
-		}
+		panic(fmt.Sprintf("RequesterArgSameAsNamedImport.Get return value 0 should be *json.RawMessage, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -560,6 +696,52 @@ func (_m *RequesterArgSameAsPkg) Get(_a0 string) {
 	assert.Equal(t, expected, gen.buf.String())
 }
 
+func TestGeneratorWhereArgumentNameConflictsWithMockedPackage(t *testing.T) {
+	parser := NewParser()
+	parser.Parse(filepath.Join(fixturePath, "requester_arg_same_as_pkg.go"))
+
+	iface, err := parser.Find("RequesterArgSameAsPkg")
+	assert.NoError(t, err)
+
+	gen := NewGenerator(iface, "fakes")
+
+	err = gen.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, gen.buf.String(), "func (_m *RequesterArgSameAsPkg) Get(_a0 string) {")
+}
+
+func TestGeneratorWhereArgumentNameShadowsGeneratedCode(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Buffer")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+
+	err = gen.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, gen.buf.String(), "func (_m *Buffer) Truncate(_a0 int) (int, error) {")
+	assert.Contains(t, gen.buf.String(), "func (_m *Buffer) Format(_a0 string, args ...interface{}) string {")
+}
+
+func TestGeneratorKeepsArgumentNamesGeneratedCodeDoesNotUse(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Sleeper")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+
+	err = gen.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, gen.buf.String(), "func (_m *Sleeper) Sleep(time int) error {")
+}
+
 func TestGeneratorHavingNoNamesOnArguments(t *testing.T) {
 	parser := NewParser()
 
@@ -583,22 +765,34 @@ type KeyManager struct {
 func (_m *KeyManager) GetKey(_a0 string, _a1 uint16) ([]byte, *Err) {
 	ret := _m.Called(_a0, _a1)
 
+	if len(ret) == 0 {
+		panic("no return value specified for KeyManager.GetKey")
+	}
+
+	if rf, ok := ret.Get(0).(func(string, uint16) ([]byte, *Err)); ok {
+		return rf(_a0, _a1)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("KeyManager.GetKey expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 []byte
 	if rf, ok := ret.Get(0).(func(string, uint16) []byte); ok {
 		r0 = rf(_a0, _a1)
+	} else if rv, ok := ret.Get(0).([]byte); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
-		}
+		panic(fmt.Sprintf("KeyManager.GetKey return value 0 should be []byte, got %#v", ret.Get(0)))
 	}
 
 	var r1 *Err
 	if rf, ok := ret.Get(1).(func(string, uint16) *Err); ok {
 		r1 = rf(_a0, _a1)
+	} else if rv, ok := ret.Get(1).(*Err); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*Err)
-		}
+		panic(fmt.Sprintf("KeyManager.GetKey return value 1 should be *Err, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -628,11 +822,17 @@ type RequesterElided struct {
 func (_m *RequesterElided) Get(path string, url string) error {
 	ret := _m.Called(path, url)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterElided.Get")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string, string) error); ok {
 		r0 = rf(path, url)
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("RequesterElided.Get return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -662,32 +862,52 @@ type RequesterReturnElided struct {
 func (_m *RequesterReturnElided) Get(path string) (int, int, int, error) {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterReturnElided.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) (int, int, int, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 4 {
+		panic(fmt.Sprintf("RequesterReturnElided.Get expects 4 return values, got %d", len(ret)))
+	}
+
 	var r0 int
 	if rf, ok := ret.Get(0).(func(string) int); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(int); ok {
+		r0 = rv
 	} else {
-		r0 = ret.Get(0).(int)
+		panic(fmt.Sprintf("RequesterReturnElided.Get return value 0 should be int, got %#v", ret.Get(0)))
 	}
 
 	var r1 int
 	if rf, ok := ret.Get(1).(func(string) int); ok {
 		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(int); ok {
+		r1 = rv
 	} else {
-		r1 = ret.Get(1).(int)
+		panic(fmt.Sprintf("RequesterReturnElided.Get return value 1 should be int, got %#v", ret.Get(1)))
 	}
 
 	var r2 int
 	if rf, ok := ret.Get(2).(func(string) int); ok {
 		r2 = rf(path)
+	} else if rv, ok := ret.Get(2).(int); ok {
+		r2 = rv
 	} else {
-		r2 = ret.Get(2).(int)
+		panic(fmt.Sprintf("RequesterReturnElided.Get return value 2 should be int, got %#v", ret.Get(2)))
 	}
 
 	var r3 error
 	if rf, ok := ret.Get(3).(func(string) error); ok {
 		r3 = rf(path)
+	} else if rv, ok := ret.Get(3).(error); ok || ret.Get(3) == nil {
+		r3 = rv
 	} else {
-		r3 = ret.Error(3)
+		panic(fmt.Sprintf("RequesterReturnElided.Get return value 3 should be error, got %#v", ret.Get(3)))
 	}
 
 	return r0, r1, r2, r3
@@ -718,11 +938,17 @@ type RequesterVariable struct {
 func (_m *RequesterVariable) Get(values ...string) bool {
 	ret := _m.Called(values)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterVariable.Get")
+	}
+
 	var r0 bool
 	if rf, ok := ret.Get(0).(func(...string) bool); ok {
 		r0 = rf(values...)
+	} else if rv, ok := ret.Get(0).(bool); ok {
+		r0 = rv
 	} else {
-		r0 = ret.Get(0).(bool)
+		panic(fmt.Sprintf("RequesterVariable.Get return value 0 should be bool, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -756,13 +982,17 @@ func (_m *Fooer) Bar(f func([]int)) {
 func (_m *Fooer) Baz(path string) func(string) string {
 	ret := _m.Called(path)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Fooer.Baz")
+	}
+
 	var r0 func(string) string
 	if rf, ok := ret.Get(0).(func(string) func(string) string); ok {
 		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(func(string) string); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(func(string) string)
-		}
+		panic(fmt.Sprintf("Fooer.Baz return value 0 should be func(string) string, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -771,11 +1001,17 @@ func (_m *Fooer) Baz(path string) func(string) string {
 func (_m *Fooer) Foo(f func(string) string) error {
 	ret := _m.Called(f)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Fooer.Foo")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(func(string) string) error); ok {
 		r0 = rf(f)
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("Fooer.Foo return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -805,13 +1041,17 @@ type AsyncProducer struct {
 func (_m *AsyncProducer) Input() chan<- bool {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for AsyncProducer.Input")
+	}
+
 	var r0 chan<- bool
 	if rf, ok := ret.Get(0).(func() chan<- bool); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(chan<- bool); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(chan<- bool)
-		}
+		panic(fmt.Sprintf("AsyncProducer.Input return value 0 should be chan<- bool, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -820,13 +1060,17 @@ func (_m *AsyncProducer) Input() chan<- bool {
 func (_m *AsyncProducer) Output() <-chan bool {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for AsyncProducer.Output")
+	}
+
 	var r0 <-chan bool
 	if rf, ok := ret.Get(0).(func() <-chan bool); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(<-chan bool); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(<-chan bool)
-		}
+		panic(fmt.Sprintf("AsyncProducer.Output return value 0 should be <-chan bool, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -835,13 +1079,17 @@ func (_m *AsyncProducer) Output() <-chan bool {
 func (_m *AsyncProducer) Whatever() chan bool {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for AsyncProducer.Whatever")
+	}
+
 	var r0 chan bool
 	if rf, ok := ret.Get(0).(func() chan bool); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(chan bool); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(chan bool)
-		}
+		panic(fmt.Sprintf("AsyncProducer.Whatever return value 0 should be chan bool, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -872,18 +1120,34 @@ type MyReader struct {
 func (_m *MyReader) Read(p []byte) (int, error) {
 	ret := _m.Called(p)
 
+	if len(ret) == 0 {
+		panic("no return value specified for MyReader.Read")
+	}
+
+	if rf, ok := ret.Get(0).(func([]byte) (int, error)); ok {
+		return rf(p)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("MyReader.Read expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 int
 	if rf, ok := ret.Get(0).(func([]byte) int); ok {
 		r0 = rf(p)
+	} else if rv, ok := ret.Get(0).(int); ok {
+		r0 = rv
 	} else {
-		r0 = ret.Get(0).(int)
+		panic(fmt.Sprintf("MyReader.Read return value 0 should be int, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func([]byte) error); ok {
 		r1 = rf(p)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("MyReader.Read return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -914,20 +1178,34 @@ type ConsulLock struct {
 func (_m *ConsulLock) Lock(_a0 <-chan struct{}) (<-chan struct{}, error) {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for ConsulLock.Lock")
+	}
+
+	if rf, ok := ret.Get(0).(func(<-chan struct{}) (<-chan struct{}, error)); ok {
+		return rf(_a0)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("ConsulLock.Lock expects 2 return values, got %d", len(ret)))
+	}
+
 	var r0 <-chan struct{}
 	if rf, ok := ret.Get(0).(func(<-chan struct{}) <-chan struct{}); ok {
 		r0 = rf(_a0)
+	} else if rv, ok := ret.Get(0).(<-chan struct{}); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(<-chan struct{})
-		}
+		panic(fmt.Sprintf("ConsulLock.Lock return value 0 should be <-chan struct{}, got %#v", ret.Get(0)))
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(<-chan struct{}) error); ok {
 		r1 = rf(_a0)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
 	} else {
-		r1 = ret.Error(1)
+		panic(fmt.Sprintf("ConsulLock.Lock return value 1 should be error, got %#v", ret.Get(1)))
 	}
 
 	return r0, r1
@@ -936,11 +1214,17 @@ func (_m *ConsulLock) Lock(_a0 <-chan struct{}) (<-chan struct{}, error) {
 func (_m *ConsulLock) Unlock() error {
 	ret := _m.Called()
 
+	if len(ret) == 0 {
+		panic("no return value specified for ConsulLock.Unlock")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func() error); ok {
 		r0 = rf()
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("ConsulLock.Unlock return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -970,11 +1254,17 @@ type Blank struct {
 func (_m *Blank) Create(x interface{}) error {
 	ret := _m.Called(x)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Blank.Create")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
 		r0 = rf(x)
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("Blank.Create return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -1004,11 +1294,17 @@ type MapFunc struct {
 func (_m *MapFunc) Get(m map[string]func(string) string) error {
 	ret := _m.Called(m)
 
+	if len(ret) == 0 {
+		panic("no return value specified for MapFunc.Get")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(map[string]func(string) string) error); ok {
 		r0 = rf(m)
+	} else if rv, ok := ret.Get(0).(error); ok || ret.Get(0) == nil {
+		r0 = rv
 	} else {
-		r0 = ret.Error(0)
+		panic(fmt.Sprintf("MapFunc.Get return value 0 should be error, got %#v", ret.Get(0)))
 	}
 
 	return r0
@@ -1042,3 +1338,263 @@ func (_m *UsesOtherPkgIface) DoSomethingElse(obj Sibling) {
 
 	assert.Equal(t, expected, gen.buf.String())
 }
+
+func TestGeneratorWithExpecter(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.WithExpecter = true
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// Requester is an autogenerated mock type for the Requester type
+type Requester struct {
+	mock.Mock
+}
+
+// Requester_Expecter sets up typed expectations on Requester
+type Requester_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Requester) EXPECT() *Requester_Expecter {
+	return &Requester_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: path
+func (_m *Requester) Get(path string) (string, error) {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Requester.Get")
+	}
+
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(path)
+	}
+
+	if len(ret) < 2 {
+		panic(fmt.Sprintf("Requester.Get expects 2 return values, got %d", len(ret)))
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(path)
+	} else if rv, ok := ret.Get(0).(string); ok {
+		r0 = rv
+	} else {
+		panic(fmt.Sprintf("Requester.Get return value 0 should be string, got %#v", ret.Get(0)))
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else if rv, ok := ret.Get(1).(error); ok || ret.Get(1) == nil {
+		r1 = rv
+	} else {
+		panic(fmt.Sprintf("Requester.Get return value 1 should be error, got %#v", ret.Get(1)))
+	}
+
+	return r0, r1
+}
+
+// Requester_Get_Call is a *mock.Call with Run and Return methods typed after Get
+type Requester_Get_Call struct {
+	*mock.Call
+}
+
+// Get sets up an expected call of Get, each argument being a value or a
+// matcher such as mock.Anything
+func (_e *Requester_Expecter) Get(path interface{}) *Requester_Get_Call {
+	return &Requester_Get_Call{Call: _e.mock.On("Get", path)}
+}
+
+func (_c *Requester_Get_Call) Run(run func(path string)) *Requester_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		_a0 := args[0].(string)
+		run(_a0)
+	})
+	return _c
+}
+
+func (_c *Requester_Get_Call) Return(_r0 string, _r1 error) *Requester_Get_Call {
+	_c.Call.Return(_r0, _r1)
+	return _c
+}
+
+func (_c *Requester_Get_Call) RunAndReturn(run func(string) (string, error)) *Requester_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorWithConstructor(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester4.go")))
+
+	iface, err := parser.Find("Requester4")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.WithConstructor = true
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// Requester4 is an autogenerated mock type for the Requester4 type
+type Requester4 struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: 
+func (_m *Requester4) Get() {
+	_m.Called()
+}
+
+// NewRequester4 creates a Requester4 registered with t, whose expectations are asserted
+// when t finishes
+func NewRequester4(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Requester4 {
+	m := &Requester4{}
+	m.Mock.Test(t)
+
+	t.Cleanup(func() { m.AssertExpectations(t) })
+
+	return m
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorConstructorName(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_unexported.go")))
+
+	iface, err := parser.Find("requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	assert.Equal(t, "newRequester", gen.constructorName())
+
+	gen.ip = true
+	assert.Equal(t, "newMockRequester", gen.constructorName())
+
+	iface.Name = "Requester"
+	assert.Equal(t, "NewMockRequester", gen.constructorName())
+}
+
+func TestGeneratorWithAssertion(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester4.go")))
+
+	iface, err := parser.Find("Requester4")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, "mocks")
+	gen.WithAssertion = true
+
+	assert.NoError(t, gen.Generate())
+	assert.Contains(t, gen.buf.String(), "type Requester4 struct {\n\tmock.Mock\n}\n\nvar _ test.Requester4 = (*Requester4)(nil)\n\n")
+
+	gen = NewGenerator(iface, pkg)
+	gen.ip = true
+	assert.Equal(t, "Requester4", gen.ifaceRef())
+}
+
+func TestGeneratorAssertionSkipsUnexportedInterfaces(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_unexported.go")))
+
+	iface, err := parser.Find("requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, "mocks")
+	assert.Equal(t, "", gen.ifaceRef())
+
+	gen.ip = true
+	assert.Equal(t, "requester", gen.ifaceRef())
+}
+
+func TestGeneratorUnrollVariadic(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.UnrollVariadic = true
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// RequesterVariable is an autogenerated mock type for the RequesterVariable type
+type RequesterVariable struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: values
+func (_m *RequesterVariable) Get(values ...string) bool {
+	_va := make([]interface{}, len(values))
+	for _i := range values {
+		_va[_i] = values[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequesterVariable.Get")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(...string) bool); ok {
+		r0 = rf(values...)
+	} else if rv, ok := ret.Get(0).(bool); ok {
+		r0 = rv
+	} else {
+		panic(fmt.Sprintf("RequesterVariable.Get return value 0 should be bool, got %#v", ret.Get(0)))
+	}
+
+	return r0
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorUnrollVariadicWithExpecter(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.UnrollVariadic = true
+	gen.WithExpecter = true
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), `func (_e *RequesterVariable_Expecter) Get(values ...interface{}) *RequesterVariable_Get_Call {
+	return &RequesterVariable_Get_Call{Call: _e.mock.On("Get", append([]interface{}{}, values...)...)}
+}`)
+	assert.Contains(t, gen.buf.String(), `	_c.Call.Run(func(args mock.Arguments) {
+		_a0 := make([]string, len(args))
+		for _i, _a := range args {
+			if _a != nil {
+				_a0[_i] = _a.(string)
+			}
+		}
+		run(_a0...)
+	})`)
+}
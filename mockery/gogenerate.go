@@ -0,0 +1,80 @@
+package mockery
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+)
+
+// GoGenerate describes the environment `go generate` sets up when mockery is
+// invoked from a //go:generate directive.
+type GoGenerate struct {
+	File    string
+	Line    int
+	Package string
+}
+
+// GoGenerateFromEnv returns the directive environment from GOFILE, GOLINE and
+// GOPACKAGE, or nil when mockery was not run by `go generate`.
+func GoGenerateFromEnv() *GoGenerate {
+	file := os.Getenv("GOFILE")
+	if file == "" {
+		return nil
+	}
+
+	line, _ := strconv.Atoi(os.Getenv("GOLINE"))
+
+	return &GoGenerate{
+		File:    file,
+		Line:    line,
+		Package: os.Getenv("GOPACKAGE"),
+	}
+}
+
+// InterfaceNames returns the interfaces the directive refers to. If the
+// declaration immediately after the directive line declares interfaces, only
+// those are returned, otherwise every interface in the file is.
+func (this *GoGenerate) InterfaceNames() ([]string, error) {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, this.File, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []string
+	var next []string
+	foundNext := false
+
+	for _, decl := range f.Decls {
+		isNext := !foundNext && fset.Position(decl.Pos()).Line > this.Line
+		if isNext {
+			foundNext = true
+		}
+
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typespec := spec.(*ast.TypeSpec)
+			if _, ok := typespec.Type.(*ast.InterfaceType); !ok {
+				continue
+			}
+
+			all = append(all, typespec.Name.Name)
+			if isNext {
+				next = append(next, typespec.Name.Name)
+			}
+		}
+	}
+
+	if len(next) > 0 {
+		return next, nil
+	}
+
+	return all, nil
+}
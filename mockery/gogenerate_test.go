@@ -0,0 +1,42 @@
+package mockery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoGenerateFromEnv(t *testing.T) {
+	os.Setenv("GOFILE", "gogenerate.go")
+	os.Setenv("GOLINE", "4")
+	os.Setenv("GOPACKAGE", "test")
+	defer os.Unsetenv("GOFILE")
+	defer os.Unsetenv("GOLINE")
+	defer os.Unsetenv("GOPACKAGE")
+
+	gogen := GoGenerateFromEnv()
+	assert.Equal(t, &GoGenerate{File: "gogenerate.go", Line: 4, Package: "test"}, gogen)
+}
+
+func TestGoGenerateNotSet(t *testing.T) {
+	os.Unsetenv("GOFILE")
+	assert.Nil(t, GoGenerateFromEnv())
+}
+
+func TestGoGenerateInterfaceAfterDirective(t *testing.T) {
+	gogen := &GoGenerate{File: filepath.Join(fixturePath, "gogenerate.go"), Line: 8}
+
+	names, err := gogen.InterfaceNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GoGenerateSecond"}, names)
+}
+
+func TestGoGenerateAllInterfacesInFile(t *testing.T) {
+	gogen := &GoGenerate{File: filepath.Join(fixturePath, "gogenerate.go"), Line: 1}
+
+	names, err := gogen.InterfaceNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GoGenerateFirst", "GoGenerateSecond"}, names)
+}
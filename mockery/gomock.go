@@ -0,0 +1,91 @@
+package mockery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateGomock writes a mock driven by a gomock Controller and its recorder,
+// in the shape mockgen gives them.
+func (g *Generator) generateGomock() {
+	mock := g.mockName()
+	recorder := mock + "MockRecorder"
+
+	g.printf("// %s is a mock of the %s interface\n", mock, g.iface.Name)
+	g.printf("type %s struct {\n", mock)
+	g.printf("\tctrl     *gomock.Controller\n")
+	g.printf("\trecorder *%s\n", recorder)
+	g.printf("}\n\n")
+
+	g.generateAssertion(g.mockName())
+
+	g.printf("// %s is the mock recorder for %s\n", recorder, mock)
+	g.printf("type %s struct {\n\tmock *%s\n}\n\n", recorder, mock)
+
+	g.printf("// %s creates a new mock instance\n", g.constructorName())
+	g.printf("func %s(ctrl *gomock.Controller) *%s {\n", g.constructorName(), mock)
+	g.printf("\tmock := &%s{ctrl: ctrl}\n", mock)
+	g.printf("\tmock.recorder = &%s{mock}\n", recorder)
+	g.printf("\treturn mock\n")
+	g.printf("}\n\n")
+
+	g.printf("// EXPECT returns an object that allows the caller to indicate expected use\n")
+	g.printf("func (_m *%s) EXPECT() *%s {\n", mock, recorder)
+	g.printf("\treturn _m.recorder\n")
+	g.printf("}\n")
+
+	for _, m := range g.methods() {
+		params, returns := m.Params, m.Returns
+
+		g.printf("\n// %s mocks base method\n", m.Name)
+		g.printf("func (_m *%s) %s%s {\n", mock, m.Name, m.signature())
+		g.printf("\t_m.ctrl.T.Helper()\n")
+
+		args := append([]string{"_m", fmt.Sprintf("%q", m.Name)}, params.Names...)
+		if m.Variadic {
+			last := len(params.Names) - 1
+			g.printf("\t_va := []interface{}{%s}\n", strings.Join(params.Names[:last], ", "))
+			g.printf("\tfor _, _a := range %s {\n", params.Names[last])
+			g.printf("\t\t_va = append(_va, _a)\n")
+			g.printf("\t}\n")
+			args = []string{"_m", fmt.Sprintf("%q", m.Name), "_va..."}
+		}
+
+		if len(returns.Types) == 0 {
+			g.printf("\t_m.ctrl.Call(%s)\n", strings.Join(args, ", "))
+		} else {
+			g.printf("\t_ret := _m.ctrl.Call(%s)\n", strings.Join(args, ", "))
+
+			var rets []string
+			for i, typ := range returns.Types {
+				g.printf("\t_ret%d, _ := _ret[%d].(%s)\n", i, i, typ)
+				rets = append(rets, fmt.Sprintf("_ret%d", i))
+			}
+			g.printf("\treturn %s\n", strings.Join(rets, ", "))
+		}
+		g.printf("}\n\n")
+
+		var matchers []string
+		for i, name := range params.Names {
+			if m.Variadic && i == len(params.Names)-1 {
+				matchers = append(matchers, name+" ...interface{}")
+			} else {
+				matchers = append(matchers, name+" interface{}")
+			}
+		}
+
+		methodType := fmt.Sprintf("reflect.TypeOf((*%s)(nil).%s)", mock, m.Name)
+		recorded := append([]string{"_mr.mock", fmt.Sprintf("%q", m.Name), methodType}, params.Names...)
+
+		g.printf("// %s indicates an expected call of %s\n", m.Name, m.Name)
+		g.printf("func (_mr *%s) %s(%s) *gomock.Call {\n", recorder, m.Name, strings.Join(matchers, ", "))
+		g.printf("\t_mr.mock.ctrl.T.Helper()\n")
+		if m.Variadic {
+			last := len(params.Names) - 1
+			g.printf("\t_va := append([]interface{}{%s}, %s...)\n", strings.Join(params.Names[:last], ", "), params.Names[last])
+			recorded = []string{"_mr.mock", fmt.Sprintf("%q", m.Name), methodType, "_va..."}
+		}
+		g.printf("\treturn _mr.mock.ctrl.RecordCallWithMethodType(%s)\n", strings.Join(recorded, ", "))
+		g.printf("}\n")
+	}
+}
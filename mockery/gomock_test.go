@@ -0,0 +1,117 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorGomock(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendGomock
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// MockRequester is a mock of the Requester interface
+type MockRequester struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequesterMockRecorder
+}
+
+// MockRequesterMockRecorder is the mock recorder for MockRequester
+type MockRequesterMockRecorder struct {
+	mock *MockRequester
+}
+
+// NewMockRequester creates a new mock instance
+func NewMockRequester(ctrl *gomock.Controller) *MockRequester {
+	mock := &MockRequester{ctrl: ctrl}
+	mock.recorder = &MockRequesterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (_m *MockRequester) EXPECT() *MockRequesterMockRecorder {
+	return _m.recorder
+}
+
+// Get mocks base method
+func (_m *MockRequester) Get(path string) (string, error) {
+	_m.ctrl.T.Helper()
+	_ret := _m.ctrl.Call(_m, "Get", path)
+	_ret0, _ := _ret[0].(string)
+	_ret1, _ := _ret[1].(error)
+	return _ret0, _ret1
+}
+
+// Get indicates an expected call of Get
+func (_mr *MockRequesterMockRecorder) Get(path interface{}) *gomock.Call {
+	_mr.mock.ctrl.T.Helper()
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Get", reflect.TypeOf((*MockRequester)(nil).Get), path)
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorGomockVariadic(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendGomock
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), `func (_m *MockRequesterVariable) Get(values ...string) bool {
+	_m.ctrl.T.Helper()
+	_va := []interface{}{}
+	for _, _a := range values {
+		_va = append(_va, _a)
+	}
+	_ret := _m.ctrl.Call(_m, "Get", _va...)
+	_ret0, _ := _ret[0].(bool)
+	return _ret0
+}`)
+	assert.Contains(t, gen.buf.String(), `func (_mr *MockRequesterVariableMockRecorder) Get(values ...interface{}) *gomock.Call {
+	_mr.mock.ctrl.T.Helper()
+	_va := append([]interface{}{}, values...)
+	return _mr.mock.ctrl.RecordCallWithMethodType(_mr.mock, "Get", reflect.TypeOf((*MockRequesterVariable)(nil).Get), _va...)
+}`)
+}
+
+func TestValidateBackend(t *testing.T) {
+	assert.NoError(t, ValidateBackend(BackendTestify))
+	assert.NoError(t, ValidateBackend(BackendGomock))
+	assert.NoError(t, ValidateBackend(BackendFuncFake))
+	assert.NoError(t, ValidateBackend(BackendSpy))
+	assert.NoError(t, ValidateBackend(BackendStub))
+	assert.NoError(t, ValidateBackend(BackendReplay))
+	assert.NoError(t, ValidateBackend(BackendDecorator))
+	assert.Error(t, ValidateBackend("mockgen"))
+}
+
+func TestGeneratorGomockWhereArgumentNameShadowsGeneratedCode(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Inspector")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendGomock
+
+	assert.NoError(t, gen.Generate())
+	assert.Contains(t, gen.buf.String(), "func (_mr *MockInspectorMockRecorder) Inspect(_a0 interface{}) *gomock.Call {")
+}
@@ -0,0 +1,44 @@
+package mockery
+
+// InterfaceInfo describes an interface found by the Walker and where its mock
+// would be written.
+type InterfaceInfo struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Methods int    `json:"methods"`
+	Output  string `json:"output"`
+}
+
+// ListingVisitor records every interface it visits without generating mocks.
+// Output paths are taken from Files; when Files is nil the mocks would be
+// printed and Output is left empty.
+type ListingVisitor struct {
+	Files      *FileOutputStreamProvider
+	Interfaces []InterfaceInfo
+}
+
+func (this *ListingVisitor) VisitWalk(iface *Interface) error {
+	pkg, err := importPath(iface)
+	if err != nil {
+		pkg = iface.Pkg.Path()
+	}
+
+	info := InterfaceInfo{
+		Name:    iface.Name,
+		Package: pkg,
+		File:    iface.Path,
+		Line:    iface.Line,
+		Methods: iface.Type.NumMethods(),
+	}
+
+	if this.Files != nil {
+		if info.Output, err = this.Files.filePath(iface); err != nil {
+			return err
+		}
+	}
+
+	this.Interfaces = append(this.Interfaces, info)
+	return nil
+}
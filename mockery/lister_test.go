@@ -0,0 +1,48 @@
+package mockery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingVisitor(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	lv := &ListingVisitor{Files: &FileOutputStreamProvider{BaseDir: "mocks"}}
+	assert.NoError(t, lv.VisitWalk(iface))
+
+	expectedPkg, err := filepath.Rel(filepath.Join(os.Getenv("GOPATH"), "src"), fixturePath)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []InterfaceInfo{{
+		Name:    "Requester",
+		Package: expectedPkg,
+		File:    testFile,
+		Line:    3,
+		Methods: 1,
+		Output:  filepath.Join("mocks", "Requester.go"),
+	}}, lv.Interfaces)
+}
+
+func TestListingVisitorFilenameTemplateError(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	tmpl, err := ParseNameTemplate("filename", `{{if eq .PackageName "pkg"}}{{.InterfaceName}}{{else}}{{index .InterfaceName 99}}{{end}}.go`)
+	require.NoError(t, err)
+
+	lv := &ListingVisitor{Files: &FileOutputStreamProvider{BaseDir: "mocks", FilenameTemplate: tmpl}}
+	assert.Error(t, lv.VisitWalk(iface))
+	assert.Empty(t, lv.Interfaces)
+}
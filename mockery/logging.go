@@ -0,0 +1,135 @@
+package mockery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel orders log messages by importance.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+var logLevelNames = map[LogLevel]string{
+	LogDebug: "debug",
+	LogInfo:  "info",
+	LogWarn:  "warn",
+	LogError: "error",
+}
+
+func (l LogLevel) String() string {
+	return logLevelNames[l]
+}
+
+// Log formats accepted by Logger.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Logger writes messages at or above Level to Out, each followed by key/value
+// fields, as text or as one JSON object per line. A nil *Logger writes info
+// messages and above as text to stderr, so stdout is left to generated code.
+type Logger struct {
+	Out    io.Writer
+	Level  LogLevel
+	Format string
+
+	mu sync.Mutex
+}
+
+var defaultLogger = &Logger{Out: os.Stderr, Level: LogInfo, Format: LogFormatText}
+
+func (l *Logger) Debug(msg string, fields ...interface{}) { l.log(LogDebug, msg, fields) }
+func (l *Logger) Info(msg string, fields ...interface{})  { l.log(LogInfo, msg, fields) }
+func (l *Logger) Warn(msg string, fields ...interface{})  { l.log(LogWarn, msg, fields) }
+func (l *Logger) Error(msg string, fields ...interface{}) { l.log(LogError, msg, fields) }
+
+func (l *Logger) log(level LogLevel, msg string, fields []interface{}) {
+	if l == nil {
+		l = defaultLogger
+	}
+
+	if level < l.Level {
+		return
+	}
+
+	var buf bytes.Buffer
+	if l.Format == LogFormatJSON {
+		l.formatJSON(&buf, level, msg, fields)
+	} else {
+		l.formatText(&buf, level, msg, fields)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Out.Write(buf.Bytes())
+}
+
+func (l *Logger) formatText(buf *bytes.Buffer, level LogLevel, msg string, fields []interface{}) {
+	if level != LogInfo {
+		buf.WriteString(strings.ToUpper(level.String()) + ": ")
+	}
+	buf.WriteString(msg)
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		value := fmt.Sprint(logValue(fields[i+1]))
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(buf, " %v=%s", fields[i], value)
+	}
+
+	buf.WriteString("\n")
+}
+
+func (l *Logger) formatJSON(buf *bytes.Buffer, level LogLevel, msg string, fields []interface{}) {
+	write := func(key string, value interface{}) {
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(value)
+		if err != nil {
+			v, _ = json.Marshal(fmt.Sprint(value))
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+
+	buf.WriteString("{")
+	write("time", time.Now().Format(time.RFC3339))
+	buf.WriteString(",")
+	write("level", level.String())
+	buf.WriteString(",")
+	write("msg", msg)
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		buf.WriteString(",")
+		write(fmt.Sprint(fields[i]), logValue(fields[i+1]))
+	}
+
+	buf.WriteString("}\n")
+}
+
+// logValue converts values that would not format usefully on their own.
+func logValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	default:
+		return v
+	}
+}
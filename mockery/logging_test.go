@@ -0,0 +1,52 @@
+package mockery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	log := &Logger{Out: &buf, Level: LogInfo}
+
+	log.Debug("Loaded package", "file", "a.go")
+	log.Info("Generated mock", "interface", "Requester", "duration", 1500*time.Microsecond)
+	log.Error("Unable to generate mock", "error", errors.New("bad type"))
+
+	expected := "Generated mock interface=Requester duration=1.5ms\n" +
+		"ERROR: Unable to generate mock error=\"bad type\"\n"
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	log := &Logger{Out: &buf, Level: LogDebug, Format: LogFormatJSON}
+
+	log.Debug("Loaded package", "file", "a.go", "interfaces", 2)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.Equal(t, "debug", entry["level"])
+	assert.Equal(t, "Loaded package", entry["msg"])
+	assert.Equal(t, "a.go", entry["file"])
+	assert.Equal(t, float64(2), entry["interfaces"])
+	assert.NotEmpty(t, entry["time"])
+}
+
+func TestLoggerQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	log := &Logger{Out: &buf, Level: LogError}
+
+	log.Info("Generated mock")
+	log.Warn("Skipping file")
+
+	assert.Empty(t, buf.String())
+}
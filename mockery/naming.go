@@ -0,0 +1,87 @@
+package mockery
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NameData is what -mockname and -filename templates are executed with.
+type NameData struct {
+	InterfaceName string
+	PackageName   string
+	InPackage     bool
+}
+
+var nameFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"lowerFirst": lowerFirst,
+	"upperFirst": upperFirst,
+	"underscore": func(s string) string { return convertCase(CaseUnderscore, s) },
+	"snake":      func(s string) string { return convertCase(CaseSnake, s) },
+	"kebab":      func(s string) string { return convertCase(CaseKebab, s) },
+}
+
+// ParseNameTemplate parses a template used to name mocks or their files. The
+// template is tried against sample data so mistakes such as unknown fields
+// are reported up front rather than for every interface.
+func ParseNameTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(nameFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, NameData{InterfaceName: "Interface", PackageName: "pkg"})
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// executeNameTemplate names iface using a template from ParseNameTemplate.
+func executeNameTemplate(tmpl *template.Template, iface *Interface, inPackage bool) (string, error) {
+	data := NameData{
+		InterfaceName: iface.Name,
+		InPackage:     inPackage,
+	}
+	if iface.Pkg != nil {
+		data.PackageName = iface.Pkg.Name()
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// nameTemplateText returns the normalized source of tmpl, or an empty string
+// if there is no template.
+func nameTemplateText(tmpl *template.Template) string {
+	if tmpl == nil || tmpl.Tree == nil {
+		return ""
+	}
+	return tmpl.Tree.Root.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return ""
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return ""
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
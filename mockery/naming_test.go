@@ -0,0 +1,57 @@
+package mockery
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNameTemplate(t *testing.T) {
+	tmpl, err := ParseNameTemplate("filename", "fake_{{.InterfaceName | snake}}.go")
+	require.NoError(t, err)
+
+	iface := &Interface{Name: "BlobStore", Pkg: types.NewPackage("example.com/store", "store")}
+	name, err := executeNameTemplate(tmpl, iface, false)
+	require.NoError(t, err)
+	assert.Equal(t, "fake_blob_store.go", name)
+}
+
+func TestParseNameTemplateHelpers(t *testing.T) {
+	tmpl, err := ParseNameTemplate("mockname", "{{.PackageName | upperFirst}}{{.InterfaceName | lowerFirst}}{{if .InPackage}}Mock{{end}}")
+	require.NoError(t, err)
+
+	iface := &Interface{Name: "Store", Pkg: types.NewPackage("example.com/store", "store")}
+	name, err := executeNameTemplate(tmpl, iface, true)
+	require.NoError(t, err)
+	assert.Equal(t, "StorestoreMock", name)
+}
+
+func TestParseNameTemplateErrors(t *testing.T) {
+	_, err := ParseNameTemplate("mockname", "{{.InterfaceName")
+	assert.Error(t, err)
+
+	_, err = ParseNameTemplate("mockname", "{{.Interface}}")
+	assert.Error(t, err)
+
+	_, err = ParseNameTemplate("mockname", "{{.InterfaceName | camel}}")
+	assert.Error(t, err)
+}
+
+func TestExecuteNameTemplateErrors(t *testing.T) {
+	// The sample data of ParseNameTemplate takes the first branch.
+	tmpl, err := ParseNameTemplate("filename", `{{if eq .PackageName "pkg"}}{{.InterfaceName}}{{else}}{{index .InterfaceName 99}}{{end}}.go`)
+	require.NoError(t, err)
+
+	iface := &Interface{Name: "Store", Pkg: types.NewPackage("example.com/store", "store")}
+	_, err = executeNameTemplate(tmpl, iface, false)
+	assert.Error(t, err)
+}
+
+func TestFirstLetterHelpersOnEmptyString(t *testing.T) {
+	assert.Equal(t, "", lowerFirst(""))
+	assert.Equal(t, "", upperFirst(""))
+	assert.Equal(t, "store", lowerFirst("Store"))
+	assert.Equal(t, "Store", upperFirst("store"))
+}
@@ -1,12 +1,12 @@
 package mockery
 
 import (
-	"fmt"
+	"go/token"
 	"io"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"text/template"
 )
 
 type Cleanup func() error
@@ -15,10 +15,42 @@ type OutputStreamProvider interface {
 	GetWriter(iface *Interface, pkg string) (io.Writer, error, Cleanup)
 }
 
+// UpToDateChecker is implemented by output stream providers that can tell
+// whether the mock they already hold for iface has the given fingerprint, in
+// which case it does not need to be regenerated.
+type UpToDateChecker interface {
+	UpToDate(iface *Interface, fingerprint string) bool
+}
+
+// DefaultOutPackage is the package of out-of-package mocks when the output
+// directory does not provide a usable name.
+const DefaultOutPackage = "mocks"
+
+// OutPackageForDir returns the package name for mocks written to dir: its base
+// name if that is a valid identifier, or DefaultOutPackage.
+func OutPackageForDir(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return DefaultOutPackage
+	}
+
+	name := filepath.Base(abs)
+	if !token.IsIdentifier(name) {
+		return DefaultOutPackage
+	}
+
+	return name
+}
+
 type StdoutStreamProvider struct {
+	// Out receives the mocks, os.Stdout if nil.
+	Out io.Writer
 }
 
 func (this *StdoutStreamProvider) GetWriter(iface *Interface, pkg string) (io.Writer, error, Cleanup) {
+	if this.Out != nil {
+		return this.Out, nil, func() error { return nil }
+	}
 	return os.Stdout, nil, func() error { return nil }
 }
 
@@ -27,22 +59,31 @@ type FileOutputStreamProvider struct {
 	InPackage bool
 	TestOnly  bool
 	Case      string
+	// FilenameTemplate, when set, names the file of each mock instead of Case
+	// and the default prefixes and suffixes.
+	FilenameTemplate *template.Template
+	// KeepTree places out-of-package mocks in the sub-directory of BaseDir
+	// matching the location of their package under SourceDir.
+	KeepTree  bool
+	SourceDir string
+	// PackageDirs are the package directories searched for interfaces. With
+	// InPackage they are scanned for orphans too, as a package whose last
+	// interface was removed produces no mock.
+	PackageDirs []string
+	Log         *Logger
+
+	produced map[string]bool
 }
 
 func (this *FileOutputStreamProvider) GetWriter(iface *Interface, pkg string) (io.Writer, error, Cleanup) {
-	var path string
-
-	caseName := iface.Name
-	if this.Case == "underscore" {
-		caseName = this.underscoreCaseName(caseName)
+	path, err := this.filePath(iface)
+	if err != nil {
+		return nil, err, func() error { return nil }
 	}
+	this.markProduced(path)
 
-	if this.InPackage {
-		path = filepath.Join(filepath.Dir(iface.Path), this.filename(caseName))
-	} else {
-		path = filepath.Join(this.BaseDir, this.filename(caseName))
+	if !this.InPackage {
 		os.MkdirAll(filepath.Dir(path), 0755)
-		pkg = filepath.Base(filepath.Dir(path))
 	}
 
 	f, err := os.Create(path)
@@ -50,12 +91,87 @@ func (this *FileOutputStreamProvider) GetWriter(iface *Interface, pkg string) (i
 		return nil, err, func() error { return nil }
 	}
 
-	fmt.Printf("Generating mock for: %s\n", iface.Name)
+	this.Log.Debug("Writing mock", "interface", iface.Name, "file", path)
 	return f, nil, func() error {
 		return f.Close()
 	}
 }
 
+func (this *FileOutputStreamProvider) UpToDate(iface *Interface, fingerprint string) bool {
+	// A file name that cannot be computed is reported by GetWriter.
+	path, err := this.filePath(iface)
+	if err != nil {
+		return false
+	}
+	this.markProduced(path)
+
+	return readFingerprint(path) == fingerprint
+}
+
+func (this *FileOutputStreamProvider) markProduced(path string) {
+	if this.produced == nil {
+		this.produced = make(map[string]bool)
+	}
+	this.produced[path] = true
+}
+
+// Prune removes the files carrying mockery's generated header from the output
+// locations that no interface visited so far maps to, and returns their
+// paths. With dryRun set the files are only listed. It only makes sense after
+// visiting every interface.
+func (this *FileOutputStreamProvider) Prune(dryRun bool) ([]string, error) {
+	orphans, err := this.orphans()
+	if err != nil || dryRun {
+		return orphans, err
+	}
+
+	for i, path := range orphans {
+		if err := os.Remove(path); err != nil {
+			return orphans[:i], err
+		}
+	}
+
+	return orphans, nil
+}
+
+// filePath returns the path the mock for iface is written to.
+func (this *FileOutputStreamProvider) filePath(iface *Interface) (string, error) {
+	var name string
+	if this.FilenameTemplate != nil {
+		var err error
+		if name, err = executeNameTemplate(this.FilenameTemplate, iface, this.InPackage); err != nil {
+			return "", err
+		}
+	} else {
+		name = this.filename(convertCase(this.Case, iface.Name))
+	}
+
+	if this.InPackage {
+		return filepath.Join(filepath.Dir(iface.Path), name), nil
+	}
+
+	if this.KeepTree {
+		return filepath.Join(this.BaseDir, this.relativeDir(iface), name), nil
+	}
+
+	return filepath.Join(this.BaseDir, name), nil
+}
+
+// relativeDir returns the directory of iface relative to SourceDir.
+func (this *FileOutputStreamProvider) relativeDir(iface *Interface) string {
+	source, err := filepath.Abs(this.SourceDir)
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(source, filepath.Dir(iface.Path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+
+	return rel
+}
+
 func (this *FileOutputStreamProvider) filename(name string) string {
 	if this.InPackage && this.TestOnly {
 		return "mock_" + name + "_test.go"
@@ -67,10 +183,6 @@ func (this *FileOutputStreamProvider) filename(name string) string {
 	return name + ".go"
 }
 
-// shamelessly taken from http://stackoverflow.com/questions/1175208/elegant-python-function-to-convert-camelcase-to-camel-caseo
 func (this *FileOutputStreamProvider) underscoreCaseName(caseName string) string {
-	rxp1 := regexp.MustCompile("(.)([A-Z][a-z]+)")
-	s1 := rxp1.ReplaceAllString(caseName, "${1}_${2}")
-	rxp2 := regexp.MustCompile("([a-z0-9])([A-Z])")
-	return strings.ToLower(rxp2.ReplaceAllString(s1, "${1}_${2}"))
+	return convertCase(CaseUnderscore, caseName)
 }
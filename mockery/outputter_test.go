@@ -1,9 +1,13 @@
 package mockery
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilenameBare(t *testing.T) {
@@ -26,6 +30,40 @@ func TestFilenameTest(t *testing.T) {
 	assert.Equal(t, "name_test.go", out.filename("name"))
 }
 
+func TestFilenameTemplate(t *testing.T) {
+	tmpl, err := ParseNameTemplate("filename", "fake_{{.InterfaceName | snake}}.go")
+	require.NoError(t, err)
+
+	out := FileOutputStreamProvider{BaseDir: "mocks", FilenameTemplate: tmpl}
+	path, err := out.filePath(&Interface{Name: "Store"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("mocks", "fake_store.go"), path)
+}
+
+func TestFilePathKeepTree(t *testing.T) {
+	source, err := filepath.Abs("src")
+	require.NoError(t, err)
+
+	out := FileOutputStreamProvider{BaseDir: "mocks", KeepTree: true, SourceDir: "src"}
+
+	iface := &Interface{Name: "Store", Path: filepath.Join(source, "internal", "db", "store.go")}
+	path, err := out.filePath(iface)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("mocks", "internal", "db", "Store.go"), path)
+
+	iface = &Interface{Name: "Root", Path: filepath.Join(source, "root.go")}
+	path, err = out.filePath(iface)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("mocks", "Root.go"), path)
+}
+
+func TestOutPackageForDir(t *testing.T) {
+	assert.Equal(t, "mocks", OutPackageForDir("./mocks"))
+	assert.Equal(t, "fakes", OutPackageForDir("internal/fakes/"))
+	assert.Equal(t, DefaultOutPackage, OutPackageForDir("my-mocks"))
+	assert.Equal(t, DefaultOutPackage, OutPackageForDir("internal/type"))
+}
+
 func TestUnderscoreCaseName(t *testing.T) {
 	assert.Equal(t, "notify_event", (&FileOutputStreamProvider{}).underscoreCaseName("NotifyEvent"))
 	assert.Equal(t, "repository", (&FileOutputStreamProvider{}).underscoreCaseName("Repository"))
@@ -34,3 +72,33 @@ func TestUnderscoreCaseName(t *testing.T) {
 	assert.Equal(t, "csv", (&FileOutputStreamProvider{}).underscoreCaseName("CSV"))
 	assert.Equal(t, "position0_size", (&FileOutputStreamProvider{}).underscoreCaseName("Position0Size"))
 }
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	generated := generatedPrefix + " v" + SemVer + ". DO NOT EDIT.\n\npackage mocks\n"
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("Current.go", generated)
+	write("Orphan.go", generated)
+	write("Handwritten.go", "package mocks\n")
+
+	out := &FileOutputStreamProvider{BaseDir: dir}
+	out.UpToDate(&Interface{Name: "Current"}, "")
+
+	orphans, err := out.Prune(true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "Orphan.go")}, orphans)
+	assert.FileExists(t, filepath.Join(dir, "Orphan.go"))
+
+	orphans, err = out.Prune(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "Orphan.go")}, orphans)
+	assert.NoFileExists(t, filepath.Join(dir, "Orphan.go"))
+	assert.FileExists(t, filepath.Join(dir, "Current.go"))
+	assert.FileExists(t, filepath.Join(dir, "Handwritten.go"))
+}
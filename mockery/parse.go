@@ -3,6 +3,8 @@ package mockery
 import (
 	"go/ast"
 	"go/importer"
+	"go/parser"
+	"go/token"
 	"go/types"
 	"io/ioutil"
 	"path/filepath"
@@ -13,6 +15,7 @@ import (
 type Parser struct {
 	file *ast.File
 	path string
+	fset *token.FileSet
 
 	pkg *types.Package
 }
@@ -32,6 +35,7 @@ func (p *Parser) Parse(path string) error {
 	var astFiles []*ast.File
 	var conf loader.Config
 
+	conf.ParserMode = parser.ParseComments
 	conf.TypeCheckFuncBodies = func(_ string) bool { return false }
 	conf.TypeChecker.DisableUnusedImportCheck = true
 	conf.TypeChecker.Importer = importer.Default()
@@ -72,6 +76,7 @@ func (p *Parser) Parse(path string) error {
 	}
 
 	p.path = abs
+	p.fset = conf.Fset
 	p.pkg = prog.Created[0].Pkg
 
 	return nil
@@ -89,7 +94,15 @@ func (p *Parser) Find(name string) (*Interface, error) {
 
 	iface := typ.Underlying().(*types.Interface).Complete()
 
-	return &Interface{name, p.path, p.file, p.pkg, iface}, nil
+	return &Interface{name, p.path, p.file, p.pkg, iface, p.line(obj)}, nil
+}
+
+func (p *Parser) line(obj types.Object) int {
+	return p.fset.Position(obj.Pos()).Line
+}
+
+func (p *Parser) declaredInFile(obj types.Object) bool {
+	return p.fset.File(obj.Pos()) == p.fset.File(p.file.Pos())
 }
 
 /*
@@ -119,8 +132,10 @@ type Interface struct {
 	File *ast.File
 	Pkg  *types.Package
 	Type *types.Interface
+	Line int
 }
 
+// Interfaces returns the interfaces declared in the parsed file.
 func (p *Parser) Interfaces() []*Interface {
 	var ifaces []*Interface
 
@@ -128,7 +143,7 @@ func (p *Parser) Interfaces() []*Interface {
 
 	for _, name := range scope.Names() {
 		obj := p.pkg.Scope().Lookup(name)
-		if obj == nil {
+		if obj == nil || !p.declaredInFile(obj) {
 			continue
 		}
 
@@ -144,7 +159,7 @@ func (p *Parser) Interfaces() []*Interface {
 			continue
 		}
 
-		ifaces = append(ifaces, &Interface{name, p.path, p.file, p.pkg, iface.Complete()})
+		ifaces = append(ifaces, &Interface{name, p.path, p.file, p.pkg, iface.Complete(), p.line(obj)})
 	}
 
 	return ifaces
@@ -35,7 +35,7 @@ func TestFileParse(t *testing.T) {
 	assert.NotNil(t, node)
 }
 
-func noTestFileInterfaces(t *testing.T) {
+func TestFileInterfaces(t *testing.T) {
 	parser := NewParser()
 
 	err := parser.Parse(testFile)
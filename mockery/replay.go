@@ -0,0 +1,126 @@
+package mockery
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// generateReplay writes, for the mock name X, an XRecorder forwarding calls to
+// an implementation of the interface and recording them, and an XReplayer
+// answering the calls of such a recording.
+func (g *Generator) generateReplay() {
+	recorder := g.mockName() + "Recorder"
+	replayer := g.mockName() + "Replayer"
+	methods := g.methods()
+
+	g.printf("// %s is a %s forwarding calls to Inner and recording them with\n// Recorder\n", recorder, g.iface.Name)
+	g.printf("type %s struct {\n", recorder)
+	g.printf("\tInner    %s\n", g.innerType(methods))
+	g.printf("\tRecorder *replay.Recorder\n")
+	g.printf("}\n\n")
+
+	g.generateAssertion(recorder)
+
+	for i, m := range methods {
+		var results []string
+		for idx := range m.Returns.Types {
+			results = append(results, fmt.Sprintf("r%d", idx))
+		}
+		args := strings.Join(g.replayedArgs(i, m), ", ")
+
+		g.printf("// %s forwards to Inner and records the call\n", m.Name)
+		g.printf("func (_m *%s) %s%s {\n", recorder, m.Name, m.signature())
+		if len(results) == 0 {
+			g.printf("\t_m.Inner.%s(%s)\n", m.Name, m.Params.callNames())
+			g.printf("\t_m.Recorder.Record(%q, []interface{}{%s}, nil)\n", m.Name, args)
+		} else {
+			g.printf("\t%s := _m.Inner.%s(%s)\n", strings.Join(results, ", "), m.Name, m.Params.callNames())
+			g.printf("\t_m.Recorder.Record(%q, []interface{}{%s}, []interface{}{%s})\n", m.Name, args, strings.Join(results, ", "))
+			g.printf("\treturn %s\n", strings.Join(results, ", "))
+		}
+		g.printf("}\n\n")
+	}
+
+	g.printf("// %s is a %s answering calls with the results recorded by a\n// %s, failing the test when a call differs from the recording\n", replayer, g.iface.Name, recorder)
+	g.printf("type %s struct {\n", replayer)
+	g.printf("\tPlayer *replay.Player\n")
+	g.printf("}\n\n")
+
+	g.generateAssertion(replayer)
+
+	for i, m := range methods {
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		var results, pointers []string
+		for idx := range m.Returns.Types {
+			results = append(results, fmt.Sprintf("r%d", idx))
+			pointers = append(pointers, fmt.Sprintf("&r%d", idx))
+		}
+
+		g.printf("// %s returns the results of the next recorded call\n", m.Name)
+		g.printf("func (_m *%s) %s%s {\n", replayer, m.Name, m.signature())
+		for idx, typ := range m.Returns.Types {
+			g.printf("\tvar r%d %s\n", idx, typ)
+		}
+		args := fmt.Sprintf("%q, []interface{}{%s}", m.Name, strings.Join(g.replayedArgs(i, m), ", "))
+		g.printf("\t_m.Player.Play(%s)\n", strings.Join(append([]string{args}, pointers...), ", "))
+		if len(results) > 0 {
+			g.printf("\treturn %s\n", strings.Join(results, ", "))
+		}
+		g.printf("}\n")
+	}
+}
+
+// replayedArgs returns the names of the parameters of m, the i-th method, that
+// are recorded and compared on replay. Contexts are left out as the replay
+// runs with different ones, and so are funcs and chans, which cannot be
+// serialized.
+func (g *Generator) replayedArgs(i int, m *method) []string {
+	params := g.iface.Type.Method(i).Type().(*types.Signature).Params()
+
+	var names []string
+	for idx, name := range m.Params.Names {
+		typ := params.At(idx).Type()
+		if m.Variadic && idx == params.Len()-1 {
+			typ = typ.(*types.Slice).Elem()
+		}
+
+		if isReplayed(typ) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func isReplayed(typ types.Type) bool {
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context" {
+			return false
+		}
+	}
+
+	switch typ.Underlying().(type) {
+	case *types.Signature, *types.Chan:
+		return false
+	}
+	return true
+}
+
+// innerType returns the type of the implementation a recorder wraps: the
+// interface itself or, when it cannot be referred to from the mock's package,
+// an interface literal with the same methods.
+func (g *Generator) innerType(methods []*method) string {
+	if ref := g.ifaceRef(); ref != "" {
+		return ref
+	}
+
+	var lines []string
+	for _, m := range methods {
+		lines = append(lines, "\t\t"+m.Name+m.signature()+"\n")
+	}
+	return "interface {\n" + strings.Join(lines, "") + "\t}"
+}
@@ -0,0 +1,86 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorReplay(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendReplay
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// RequesterRecorder is a Requester forwarding calls to Inner and recording them with
+// Recorder
+type RequesterRecorder struct {
+	Inner    Requester
+	Recorder *replay.Recorder
+}
+
+// Get forwards to Inner and records the call
+func (_m *RequesterRecorder) Get(path string) (string, error) {
+	r0, r1 := _m.Inner.Get(path)
+	_m.Recorder.Record("Get", []interface{}{path}, []interface{}{r0, r1})
+	return r0, r1
+}
+
+// RequesterReplayer is a Requester answering calls with the results recorded by a
+// RequesterRecorder, failing the test when a call differs from the recording
+type RequesterReplayer struct {
+	Player *replay.Player
+}
+
+// Get returns the results of the next recorded call
+func (_m *RequesterReplayer) Get(path string) (string, error) {
+	var r0 string
+	var r1 error
+	_m.Player.Play("Get", []interface{}{path}, &r0, &r1)
+	return r0, r1
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorReplayUnexportedInterface(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_unexported.go")))
+
+	iface, err := parser.Find("requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, "mocks")
+	gen.Backend = BackendReplay
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "\tInner    interface {\n\t\tGet()\n\t}\n")
+	assert.Contains(t, gen.buf.String(), "\t_m.Player.Play(\"Get\", []interface{}{})\n")
+}
+
+func TestGeneratorReplaySkipsContextFuncAndChanArguments(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_context.go")))
+
+	iface, err := parser.Find("RequesterContext")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendReplay
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), `	r0, r1 := _m.Inner.Get(ctx, path, progress, done)
+	_m.Recorder.Record("Get", []interface{}{path}, []interface{}{r0, r1})`)
+	assert.Contains(t, gen.buf.String(), `	_m.Player.Play("Get", []interface{}{path}, &r0, &r1)`)
+}
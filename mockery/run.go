@@ -0,0 +1,365 @@
+package mockery
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/vektra/errors"
+)
+
+const regexMetadataChars = "\\.+*?()|[]{}^$"
+
+// Options configures Run. Each field corresponds to a flag of the mockery
+// command; the zero value of a field means the flag's default.
+type Options struct {
+	// Name is the name of, or a regular expression matching, the interfaces
+	// to mock. Exactly one of Name, All or GoGenerate selects interfaces,
+	// except for List which selects everything by default.
+	Name string
+	// All mocks every interface found under Dir, recursively.
+	All bool
+	// GoGenerate selects the interfaces a //go:generate directive refers to.
+	GoGenerate *GoGenerate
+	// Dir is the directory searched for interfaces, "." by default.
+	Dir string
+	// Recursive searches the sub-directories of Dir when using Name.
+	Recursive bool
+
+	// Output is the directory mocks are written to, "./mocks" by default.
+	Output string
+	// Print writes mocks to Stdout, os.Stdout by default, instead of files.
+	Print  bool
+	Stdout io.Writer
+	// InPackage generates mocks inside the package of their interface.
+	InPackage bool
+	// TestOnly writes mocks to _test.go files.
+	TestOnly bool
+	// Case is the casing style of file names, CaseCamel by default.
+	Case string
+	// Note is a comment inserted in the prologue of each mock, lines
+	// separated by a literal \n.
+	Note string
+	// OutPackage names the package of out-of-package mocks, the name of
+	// Output by default.
+	OutPackage string
+	// KeepTree mirrors the source packages under Output.
+	KeepTree bool
+	// MockName and FileName are templates naming mocks and their files, see
+	// ParseNameTemplate.
+	MockName string
+	FileName string
+	// Force regenerates mocks whose fingerprint is unchanged.
+	Force bool
+	// WithExpecter generates typed expectation helpers, see
+	// Generator.WithExpecter.
+	WithExpecter bool
+	// WithConstructor generates a constructor asserting expectations when
+	// the test finishes, see Generator.WithConstructor.
+	WithConstructor bool
+	// WithAssertion generates a compile-time check that each mock implements
+	// its interface, see Generator.WithAssertion.
+	WithAssertion bool
+	// UnrollVariadic passes variadic arguments to Called one by one, see
+	// Generator.UnrollVariadic.
+	UnrollVariadic bool
+	// Backend selects the kind of mock generated, BackendTestify by default.
+	// WithExpecter, WithConstructor and UnrollVariadic require BackendTestify.
+	Backend string
+	// Template is the path of a text/template generating the code for each
+	// interface in place of the backend, see TemplateData. It cannot be
+	// combined with other backends or the testify options.
+	Template string
+
+	// List reports the interfaces found in Result.Interfaces instead of
+	// generating mocks.
+	List bool
+	// Check compares the mocks against the files on disk without writing
+	// them, see ErrStale.
+	Check bool
+	// Prune removes orphaned mocks after generating, requires All. With
+	// DryRun the orphans are only reported.
+	Prune  bool
+	DryRun bool
+	// Watch keeps regenerating mocks as interfaces change until the context
+	// passed to Run is done.
+	Watch bool
+
+	// Log receives progress and errors, see Logger for the default.
+	Log *Logger
+}
+
+// Result reports what Run did.
+type Result struct {
+	// Generated and Unchanged name the interfaces whose mocks were written
+	// or skipped because their fingerprint matched.
+	Generated []string
+	Unchanged []string
+	// Checked names the interfaces whose mocks were compared in Check mode.
+	Checked []string
+	// Interfaces lists the interfaces found in List mode.
+	Interfaces []InterfaceInfo
+	// Missing, Outdated and Orphaned list the stale mocks in Check mode.
+	// Orphans are only looked for when All is set.
+	Missing  []string
+	Outdated []string
+	Orphaned []string
+	// Pruned lists the files removed, or that would be with DryRun, by Prune.
+	Pruned []string
+}
+
+var (
+	// ErrInterfaceNotFound is returned when Name or GoGenerate matched no
+	// interface.
+	ErrInterfaceNotFound = errors.New("unable to find interface in any go files under this path")
+	// ErrStale is returned in Check mode when mocks are missing, outdated or
+	// orphaned. The Result lists them.
+	ErrStale = errors.New("mocks are not up to date")
+)
+
+// Run generates, lists or checks mocks as described by opts. It is what the
+// mockery command runs once its flags are parsed.
+func Run(ctx context.Context, opts Options) (Result, error) {
+	var result Result
+
+	opts.setDefaults()
+
+	walker, err := opts.walker()
+	if err != nil {
+		return result, err
+	}
+
+	if err := opts.validate(); err != nil {
+		return result, err
+	}
+
+	var mockNameTemplate, fileNameTemplate *template.Template
+	if opts.MockName != "" {
+		if mockNameTemplate, err = ParseNameTemplate("mockname", opts.MockName); err != nil {
+			return result, fmt.Errorf("invalid MockName template: %s", err)
+		}
+	}
+	if opts.FileName != "" {
+		if fileNameTemplate, err = ParseNameTemplate("filename", opts.FileName); err != nil {
+			return result, fmt.Errorf("invalid FileName template: %s", err)
+		}
+	}
+
+	var codeTemplate *template.Template
+	if opts.Template != "" {
+		if codeTemplate, err = ParseTemplate(opts.Template); err != nil {
+			return result, fmt.Errorf("invalid Template: %s", err)
+		}
+	}
+
+	var osp OutputStreamProvider
+	var files *FileOutputStreamProvider
+	if opts.Print {
+		osp = &StdoutStreamProvider{Out: opts.Stdout}
+	} else {
+		files = &FileOutputStreamProvider{
+			BaseDir:          opts.Output,
+			InPackage:        opts.InPackage,
+			TestOnly:         opts.TestOnly,
+			Case:             opts.Case,
+			FilenameTemplate: fileNameTemplate,
+			KeepTree:         opts.KeepTree,
+			SourceDir:        opts.Dir,
+			Log:              opts.Log,
+		}
+		osp = files
+	}
+
+	if opts.List {
+		lister := &ListingVisitor{Files: files}
+		walker.Walk(lister)
+
+		result.Interfaces = lister.Interfaces
+		return result, walker.Err()
+	}
+
+	var checker *CheckingOutputStreamProvider
+	if opts.Check {
+		checker = &CheckingOutputStreamProvider{Files: files}
+		osp = checker
+	}
+
+	visitor := &GeneratorVisitor{
+		InPackage:        opts.InPackage,
+		OutPackage:       opts.OutPackage,
+		KeepTree:         opts.KeepTree,
+		Note:             opts.Note,
+		Force:            opts.Force,
+		WithExpecter:     opts.WithExpecter,
+		WithConstructor:  opts.WithConstructor,
+		WithAssertion:    opts.WithAssertion,
+		UnrollVariadic:   opts.UnrollVariadic,
+		Backend:          opts.Backend,
+		Template:         codeTemplate,
+		MockNameTemplate: mockNameTemplate,
+		Osp:              osp,
+		Log:              opts.Log,
+	}
+
+	generated := walker.Walk(visitor)
+
+	result.Generated = visitor.Generated
+	result.Unchanged = visitor.Unchanged
+	result.Checked = visitor.Checked
+
+	if err := walker.Err(); err != nil {
+		return result, err
+	}
+
+	if !opts.All && !generated {
+		return result, ErrInterfaceNotFound
+	}
+
+	if checker != nil {
+		result.Missing = checker.Missing
+		result.Outdated = checker.Outdated
+
+		if opts.All {
+			files.PackageDirs = walker.Dirs()
+			if result.Orphaned, err = checker.Orphans(); err != nil {
+				return result, err
+			}
+		}
+
+		if len(result.Missing)+len(result.Outdated)+len(result.Orphaned) > 0 {
+			return result, ErrStale
+		}
+		return result, nil
+	}
+
+	if opts.Prune {
+		// A package that failed to parse hides all of its interfaces, so
+		// their mocks would look orphaned.
+		if skipped := walker.Skipped(); len(skipped) > 0 {
+			return result, fmt.Errorf("not pruning because %d files failed to parse, such as %s", len(skipped), skipped[0])
+		}
+
+		files.PackageDirs = walker.Dirs()
+		result.Pruned, err = files.Prune(opts.DryRun)
+		for _, path := range result.Pruned {
+			if opts.DryRun {
+				opts.Log.Info("Would remove orphaned mock", "file", path)
+			} else {
+				opts.Log.Info("Removed orphaned mock", "file", path)
+			}
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if opts.Watch {
+		watcher := &Watcher{Walker: walker}
+		if files != nil && !opts.InPackage {
+			watcher.Ignore = []string{opts.Output}
+		}
+
+		opts.Log.Info("Watching for changes", "dir", opts.Dir)
+		watcher.Watch(ctx, visitor)
+
+		result.Generated = visitor.Generated
+		result.Unchanged = visitor.Unchanged
+	}
+
+	return result, nil
+}
+
+func (opts *Options) setDefaults() {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Output == "" {
+		opts.Output = "./mocks"
+	}
+	if opts.Case == "" {
+		opts.Case = CaseCamel
+	}
+	if opts.Backend == "" {
+		opts.Backend = BackendTestify
+	}
+	if opts.OutPackage == "" && !opts.KeepTree {
+		opts.OutPackage = OutPackageForDir(opts.Output)
+	}
+}
+
+func (opts *Options) validate() error {
+	if opts.Check && opts.Print {
+		return errors.New("specify Check or Print, but not both")
+	} else if opts.Check && opts.Watch {
+		return errors.New("specify Check or Watch, but not both")
+	} else if opts.Prune && (!opts.All || opts.Print || opts.Check) {
+		return errors.New("use Prune with All, and without Print or Check")
+	} else if opts.KeepTree && (opts.InPackage || opts.OutPackage != "") {
+		return errors.New("use KeepTree without InPackage or OutPackage")
+	} else if !token.IsIdentifier(opts.OutPackage) && !opts.KeepTree {
+		return fmt.Errorf("invalid OutPackage %q", opts.OutPackage)
+	} else if opts.Backend != BackendTestify && (opts.WithExpecter || opts.WithConstructor || opts.UnrollVariadic) {
+		return fmt.Errorf("WithExpecter, WithConstructor and UnrollVariadic require the %s backend", BackendTestify)
+	} else if opts.Template != "" && (opts.Backend != BackendTestify || opts.WithExpecter || opts.WithConstructor || opts.WithAssertion || opts.UnrollVariadic) {
+		return errors.New("use Template without Backend, WithExpecter, WithConstructor, WithAssertion or UnrollVariadic")
+	}
+
+	if err := ValidateBackend(opts.Backend); err != nil {
+		return err
+	}
+
+	return ValidateCase(opts.Case)
+}
+
+// walker returns a Walker selecting the interfaces opts refers to.
+func (opts *Options) walker() (Walker, error) {
+	walker := Walker{
+		BaseDir: opts.Dir,
+		Log:     opts.Log,
+	}
+
+	if opts.Name != "" && opts.All {
+		return walker, errors.New("specify Name or All, but not both")
+	} else if opts.Name != "" {
+		walker.Recursive = opts.Recursive
+		if strings.ContainsAny(opts.Name, regexMetadataChars) {
+			filter, err := regexp.Compile(opts.Name)
+			if err != nil {
+				return walker, fmt.Errorf("invalid regular expression provided as Name: %s", err)
+			}
+			walker.Filter = filter
+		} else {
+			walker.Filter = regexp.MustCompile(fmt.Sprintf("^%s$", opts.Name))
+			walker.LimitOne = true
+		}
+	} else if opts.All {
+		walker.Recursive = true
+		walker.Filter = regexp.MustCompile(".*")
+	} else if opts.GoGenerate != nil {
+		names, err := opts.GoGenerate.InterfaceNames()
+		if err != nil {
+			return walker, fmt.Errorf("unable to read %s: %s", opts.GoGenerate.File, err)
+		} else if len(names) == 0 {
+			return walker, fmt.Errorf("no interfaces found in %s of package %s", opts.GoGenerate.File, opts.GoGenerate.Package)
+		}
+
+		opts.Name = strings.Join(names, ", ")
+		for i, name := range names {
+			names[i] = regexp.QuoteMeta(name)
+		}
+
+		walker.Filter = regexp.MustCompile(fmt.Sprintf("^(%s)$", strings.Join(names, "|")))
+		walker.LimitOne = len(names) == 1
+	} else if opts.List {
+		walker.Recursive = true
+		walker.Filter = regexp.MustCompile(".*")
+	} else {
+		return walker, errors.New("use Name to specify the name of the interface or All for all interfaces found")
+	}
+
+	return walker, nil
+}
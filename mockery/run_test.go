@@ -0,0 +1,132 @@
+package mockery
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRejectsInvalidOptions(t *testing.T) {
+	for _, opts := range []Options{
+		{},
+		{Name: "Requester", All: true},
+		{Name: "Requester", Check: true, Print: true},
+		{Name: "Requester", Check: true, Watch: true},
+		{Name: "Requester", Prune: true},
+		{All: true, Prune: true, Print: true},
+		{Name: "Requester", KeepTree: true, InPackage: true},
+		{Name: "Requester", KeepTree: true, OutPackage: "fakes"},
+		{Name: "Requester", OutPackage: "not-a-package"},
+		{Name: "Requester", Case: "upper"},
+		{Name: "Requester", MockName: "{{.Missing}}"},
+		{Name: "Request(er"},
+		{Name: "Requester", Backend: "mockgen"},
+		{Name: "Requester", Backend: BackendGomock, WithExpecter: true},
+		{Name: "Requester", Template: "missing.tmpl"},
+		{Name: "Requester", Template: "gen.tmpl", Backend: BackendStub},
+		{Name: "Requester", Template: "gen.tmpl", WithAssertion: true},
+	} {
+		_, err := Run(context.Background(), opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
+
+func TestRunPrintsMock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := "package runner\n\ntype Runner interface {\n\tRun() error\n}\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "runner.go"), []byte(src), 0644))
+
+	var out bytes.Buffer
+	result, err := Run(context.Background(), Options{
+		Name:      "Runner",
+		Dir:       dir,
+		InPackage: true,
+		Print:     true,
+		Stdout:    &out,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Runner"}, result.Generated)
+	assert.Contains(t, out.String(), "type MockRunner struct")
+
+	_, err = Run(context.Background(), Options{Name: "Walker", Dir: dir, Print: true, Stdout: &out})
+	assert.Equal(t, ErrInterfaceNotFound, err)
+}
+
+func TestRunPruneKeepsMocksWhenSourcesFailToParse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	output := filepath.Join(dir, "mocks")
+	require.NoError(t, os.Mkdir(src, 0755))
+
+	beta := "package b\n\ntype Beta interface {\n\tGet() error\n}\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "beta.go"), []byte(beta), 0644))
+
+	opts := Options{All: true, Dir: src, Output: output, Prune: true}
+
+	result, err := Run(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Beta"}, result.Generated)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "wip.go"), []byte("package b\n\nfunc {\n"), 0644))
+
+	result, err = Run(context.Background(), opts)
+	assert.Error(t, err)
+	assert.Empty(t, result.Pruned)
+	assert.FileExists(t, filepath.Join(output, "Beta.go"))
+}
+
+func TestRunCheckDoesNotReportGeneratedMocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := "package runner\n\ntype Runner interface {\n\tRun() error\n}\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "runner.go"), []byte(src), 0644))
+
+	opts := Options{Name: "Runner", Dir: dir, Output: filepath.Join(dir, "mocks")}
+
+	_, err = Run(context.Background(), opts)
+	require.NoError(t, err)
+
+	opts.Check = true
+	result, err := Run(context.Background(), opts)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Generated)
+	assert.Equal(t, []string{"Runner"}, result.Checked)
+}
+
+func TestRunPruneRemovesInPackageMockOfRemovedInterface(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pkgDir := filepath.Join(dir, "b")
+	require.NoError(t, os.Mkdir(pkgDir, 0755))
+
+	// Beta, the last interface of package b, was turned into a struct.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pkgDir, "beta.go"), []byte("package b\n\ntype Beta struct{}\n"), 0644))
+
+	orphan := filepath.Join(pkgDir, "mock_Beta.go")
+	generated := generatedPrefix + " v" + SemVer + ". DO NOT EDIT.\n\npackage b\n"
+	require.NoError(t, ioutil.WriteFile(orphan, []byte(generated), 0644))
+
+	result, err := Run(context.Background(), Options{All: true, Dir: dir, InPackage: true, Prune: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{orphan}, result.Pruned)
+	assert.NoFileExists(t, orphan)
+}
@@ -0,0 +1,154 @@
+package mockery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generateSpy writes a spy recording the arguments of each call to a method
+// X, which XCallCount and XArgsForCall report, and returning the results set
+// with XReturns or, for a given call, XReturnsOnCall. A mutex per method
+// guards its calls and results.
+func (g *Generator) generateSpy() {
+	spy := g.mockName()
+	methods := g.methods()
+
+	g.printf("// %s is a spy of the %s interface recording its calls\n", spy, g.iface.Name)
+	g.printf("type %s struct {\n", spy)
+	for _, m := range methods {
+		field := lowerFirst(m.Name)
+		g.printf("\t%sMutex sync.RWMutex\n", field)
+		g.printf("\t%sArgsForCall []%s\n", field, spyArgs(m.Params))
+		if len(m.Returns.Types) > 0 {
+			g.printf("\t%sReturns %s\n", field, spyResults(m.Returns))
+			g.printf("\t%sReturnsOnCall map[int]%s\n", field, spyResults(m.Returns))
+		}
+	}
+	g.printf("}\n\n")
+
+	g.generateAssertion(g.mockName())
+
+	for i, m := range methods {
+		if i > 0 {
+			g.printf("\n")
+		}
+		g.generateSpyMethod(spy, m)
+	}
+}
+
+// spyArgs returns the type of the struct recording the arguments of a call.
+func spyArgs(params *paramList) string {
+	var fields []string
+	for i, name := range params.Names {
+		typ := params.Types[i]
+		if strings.HasPrefix(typ, "...") {
+			typ = "[]" + typ[3:]
+		}
+		fields = append(fields, name+" "+typ)
+	}
+
+	if len(fields) == 0 {
+		return "struct{}"
+	}
+	return "struct {\n\t\t" + strings.Join(fields, "\n\t\t") + "\n\t}"
+}
+
+// spyResults returns the type of the struct holding the results of a call.
+func spyResults(returns *paramList) string {
+	var fields []string
+	for i, typ := range returns.Types {
+		fields = append(fields, fmt.Sprintf("r%d %s", i, typ))
+	}
+
+	return "struct {\n\t\t" + strings.Join(fields, "\n\t\t") + "\n\t}"
+}
+
+func (g *Generator) generateSpyMethod(spy string, m *method) {
+	field := lowerFirst(m.Name)
+	params, returns := m.Params, m.Returns
+
+	// The variadic arguments are copied, as the caller may reuse their slice.
+	var args []string
+	for i, name := range params.Names {
+		if strings.HasPrefix(params.Types[i], "...") {
+			name = fmt.Sprintf("append([]%s(nil), %s...)", params.Types[i][3:], name)
+		}
+		args = append(args, name)
+	}
+
+	var results, resultNames, resultFields []string
+	for i, typ := range returns.Types {
+		results = append(results, fmt.Sprintf("r%d %s", i, typ))
+		resultNames = append(resultNames, fmt.Sprintf("r%d", i))
+		resultFields = append(resultFields, fmt.Sprintf("_r.r%d", i))
+	}
+
+	if len(returns.Types) > 0 {
+		g.printf("// %s records its arguments and returns the results stubbed for this call\n", m.Name)
+	} else {
+		g.printf("// %s records its arguments\n", m.Name)
+	}
+	g.printf("func (_s *%s) %s%s {\n", spy, m.Name, m.signature())
+	g.printf("\t_s.%sMutex.Lock()\n", field)
+	g.printf("\tdefer _s.%sMutex.Unlock()\n\n", field)
+	g.printf("\t_s.%sArgsForCall = append(_s.%sArgsForCall, %s{%s})\n", field, field, spyArgs(params), strings.Join(args, ", "))
+	if len(returns.Types) > 0 {
+		g.printf("\n\tif _r, ok := _s.%sReturnsOnCall[len(_s.%sArgsForCall)-1]; ok {\n", field, field)
+		g.printf("\t\treturn %s\n", strings.Join(resultFields, ", "))
+		g.printf("\t}\n")
+		g.printf("\t_r := _s.%sReturns\n", field)
+		g.printf("\treturn %s\n", strings.Join(resultFields, ", "))
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sCallCount returns the number of calls to %s\n", m.Name, m.Name)
+	g.printf("func (_s *%s) %sCallCount() int {\n", spy, m.Name)
+	g.printf("\t_s.%sMutex.RLock()\n", field)
+	g.printf("\tdefer _s.%sMutex.RUnlock()\n\n", field)
+	g.printf("\treturn len(_s.%sArgsForCall)\n", field)
+	g.printf("}\n")
+
+	if len(params.Names) > 0 {
+		var argTypes, argFields []string
+		for i, name := range params.Names {
+			typ := params.Types[i]
+			if strings.HasPrefix(typ, "...") {
+				typ = "[]" + typ[3:]
+			}
+			argTypes = append(argTypes, typ)
+			argFields = append(argFields, "_a."+name)
+		}
+
+		argResults := strings.Join(argTypes, ", ")
+		if len(argTypes) > 1 {
+			argResults = "(" + argResults + ")"
+		}
+
+		g.printf("\n// %sArgsForCall returns the arguments of the i-th call to %s, counting from 0\n", m.Name, m.Name)
+		g.printf("func (_s *%s) %sArgsForCall(i int) %s {\n", spy, m.Name, argResults)
+		g.printf("\t_s.%sMutex.RLock()\n", field)
+		g.printf("\tdefer _s.%sMutex.RUnlock()\n\n", field)
+		g.printf("\t_a := _s.%sArgsForCall[i]\n", field)
+		g.printf("\treturn %s\n", strings.Join(argFields, ", "))
+		g.printf("}\n")
+	}
+
+	if len(returns.Types) > 0 {
+		g.printf("\n// %sReturns sets the results of the calls to %s\n", m.Name, m.Name)
+		g.printf("func (_s *%s) %sReturns(%s) {\n", spy, m.Name, strings.Join(results, ", "))
+		g.printf("\t_s.%sMutex.Lock()\n", field)
+		g.printf("\tdefer _s.%sMutex.Unlock()\n\n", field)
+		g.printf("\t_s.%sReturns = %s{%s}\n", field, spyResults(returns), strings.Join(resultNames, ", "))
+		g.printf("}\n")
+
+		g.printf("\n// %sReturnsOnCall sets the results of the i-th call to %s, counting from 0,\n// overriding %sReturns\n", m.Name, m.Name, m.Name)
+		g.printf("func (_s *%s) %sReturnsOnCall(i int, %s) {\n", spy, m.Name, strings.Join(results, ", "))
+		g.printf("\t_s.%sMutex.Lock()\n", field)
+		g.printf("\tdefer _s.%sMutex.Unlock()\n\n", field)
+		g.printf("\tif _s.%sReturnsOnCall == nil {\n", field)
+		g.printf("\t\t_s.%sReturnsOnCall = make(map[int]%s)\n", field, spyResults(returns))
+		g.printf("\t}\n")
+		g.printf("\t_s.%sReturnsOnCall[i] = %s{%s}\n", field, spyResults(returns), strings.Join(resultNames, ", "))
+		g.printf("}\n")
+	}
+}
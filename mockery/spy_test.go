@@ -0,0 +1,136 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorSpy(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester2.go")))
+
+	iface, err := parser.Find("Requester2")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendSpy
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// Requester2 is a spy of the Requester2 interface recording its calls
+type Requester2 struct {
+	getMutex sync.RWMutex
+	getArgsForCall []struct {
+		path string
+	}
+	getReturns struct {
+		r0 error
+	}
+	getReturnsOnCall map[int]struct {
+		r0 error
+	}
+}
+
+// Get records its arguments and returns the results stubbed for this call
+func (_s *Requester2) Get(path string) error {
+	_s.getMutex.Lock()
+	defer _s.getMutex.Unlock()
+
+	_s.getArgsForCall = append(_s.getArgsForCall, struct {
+		path string
+	}{path})
+
+	if _r, ok := _s.getReturnsOnCall[len(_s.getArgsForCall)-1]; ok {
+		return _r.r0
+	}
+	_r := _s.getReturns
+	return _r.r0
+}
+
+// GetCallCount returns the number of calls to Get
+func (_s *Requester2) GetCallCount() int {
+	_s.getMutex.RLock()
+	defer _s.getMutex.RUnlock()
+
+	return len(_s.getArgsForCall)
+}
+
+// GetArgsForCall returns the arguments of the i-th call to Get, counting from 0
+func (_s *Requester2) GetArgsForCall(i int) string {
+	_s.getMutex.RLock()
+	defer _s.getMutex.RUnlock()
+
+	_a := _s.getArgsForCall[i]
+	return _a.path
+}
+
+// GetReturns sets the results of the calls to Get
+func (_s *Requester2) GetReturns(r0 error) {
+	_s.getMutex.Lock()
+	defer _s.getMutex.Unlock()
+
+	_s.getReturns = struct {
+		r0 error
+	}{r0}
+}
+
+// GetReturnsOnCall sets the results of the i-th call to Get, counting from 0,
+// overriding GetReturns
+func (_s *Requester2) GetReturnsOnCall(i int, r0 error) {
+	_s.getMutex.Lock()
+	defer _s.getMutex.Unlock()
+
+	if _s.getReturnsOnCall == nil {
+		_s.getReturnsOnCall = make(map[int]struct {
+		r0 error
+	})
+	}
+	_s.getReturnsOnCall[i] = struct {
+		r0 error
+	}{r0}
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorSpyVariadic(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendSpy
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "\t_s.getArgsForCall = append(_s.getArgsForCall, struct {\n\t\tvalues []string\n\t}{append([]string(nil), values...)})\n")
+	assert.Contains(t, gen.buf.String(), "func (_s *RequesterVariable) GetArgsForCall(i int) []string {\n")
+}
+
+func TestGeneratorSpyWhereArgumentNameShadowsGeneratedCode(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Buffer")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendSpy
+
+	assert.NoError(t, gen.Generate())
+	assert.Contains(t, gen.buf.String(), `func (_s *Buffer) Truncate(_a0 int) (int, error) {
+	_s.truncateMutex.Lock()
+	defer _s.truncateMutex.Unlock()
+
+	_s.truncateArgsForCall = append(_s.truncateArgsForCall, struct {
+		_a0 int
+	}{_a0})
+
+	if _r, ok := _s.truncateReturnsOnCall[len(_s.truncateArgsForCall)-1]; ok {`)
+}
@@ -0,0 +1,57 @@
+package mockery
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// generateStub writes a stub whose methods ignore their arguments and return
+// the zero value of each result: nil for the nillable types, an unset
+// variable for the others.
+func (g *Generator) generateStub() {
+	stub := g.mockName()
+
+	g.printf("// %s is a stub of the %s interface whose methods do nothing and\n// return zero values\n", stub, g.iface.Name)
+	g.printf("type %s struct{}\n\n", stub)
+
+	g.generateAssertion(g.mockName())
+
+	for i, m := range g.methods() {
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		if len(m.Returns.Types) == 0 {
+			g.printf("// %s does nothing\n", m.Name)
+			g.printf("func (_s *%s) %s%s {}\n", stub, m.Name, m.signature())
+			continue
+		}
+
+		g.printf("// %s returns zero values\n", m.Name)
+		g.printf("func (_s *%s) %s%s {\n", stub, m.Name, m.signature())
+
+		sig := g.iface.Type.Method(i).Type().(*types.Signature)
+
+		var results []string
+		for idx, typ := range m.Returns.Types {
+			if m.Returns.Nilable[idx] && !isArray(sig.Results().At(idx).Type()) {
+				results = append(results, "nil")
+				continue
+			}
+
+			g.printf("\tvar r%d %s\n", idx, typ)
+			results = append(results, fmt.Sprintf("r%d", idx))
+		}
+
+		g.printf("\treturn %s\n", strings.Join(results, ", "))
+		g.printf("}\n")
+	}
+}
+
+// isArray reports whether typ is an array. isNillable counts arrays so testify
+// mocks accept nil for them, but nil is not a value of an array type.
+func isArray(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Array)
+	return ok
+}
@@ -0,0 +1,65 @@
+package mockery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorStub(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendStub
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `// Requester is a stub of the Requester interface whose methods do nothing and
+// return zero values
+type Requester struct{}
+
+// Get returns zero values
+func (_s *Requester) Get(path string) (string, error) {
+	var r0 string
+	return r0, nil
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorStubNothing(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester4.go")))
+
+	iface, err := parser.Find("Requester4")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendStub
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "// Get does nothing\nfunc (_s *Requester4) Get() {}\n")
+}
+
+func TestGeneratorStubArray(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_array.go")))
+
+	iface, err := parser.Find("RequesterArray")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Backend = BackendStub
+
+	assert.NoError(t, gen.Generate())
+
+	assert.Contains(t, gen.buf.String(), "\tvar r0 [2]string\n\treturn r0, nil\n")
+}
@@ -0,0 +1,264 @@
+package mockery
+
+import (
+	"bytes"
+	"go/ast"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is what a -template is executed with, once per interface. Its
+// output follows the package clause and the imports of the generated file, and
+// may start with import declarations of its own.
+type TemplateData struct {
+	// InterfaceName is the name of the interface, MockName the name of the
+	// mock as given by -mockname or the default naming.
+	InterfaceName string
+	MockName      string
+	// PackageName is the package the generated code goes in and InPackage
+	// whether it is the package declaring the interface.
+	PackageName string
+	InPackage   bool
+	// SourcePackage and SourcePath are the name and import path of the
+	// package declaring the interface.
+	SourcePackage string
+	SourcePath    string
+	// InterfaceRef is how the generated code refers to the interface, such as
+	// pkg.Iface, or empty when it cannot: unexported interfaces and those in
+	// main can only be referred to from their own package.
+	InterfaceRef string
+	// Doc is the doc comment of the interface.
+	Doc string
+	// Imports are those of the file declaring the interface.
+	Imports []TemplateImport
+	Methods []TemplateMethod
+}
+
+// TemplateImport is an import of the file declaring the interface. Name is
+// empty unless the import is renamed.
+type TemplateImport struct {
+	Name string
+	Path string
+}
+
+// TemplateMethod is a method of the interface.
+type TemplateMethod struct {
+	Name string
+	// Doc is the doc comment of the method, empty for methods of embedded
+	// interfaces.
+	Doc     string
+	Params  []TemplateParam
+	Results []TemplateParam
+	// Variadic is true if the last parameter is variadic.
+	Variadic bool
+	// Signature is the parameters and results as written after the method
+	// name in a declaration.
+	Signature string
+}
+
+// TemplateParam is a parameter or result of a method. Name is never empty:
+// unnamed parameters and those whose name would shadow an import of the
+// interface's file or the generated package are named _a0, _a1, and so on,
+// results r0, r1, and so on.
+type TemplateParam struct {
+	Name string
+	// Type is rendered for the generated package, ...T for the variadic
+	// parameter.
+	Type     string
+	Nillable bool
+	Variadic bool
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"quote": strconv.Quote,
+	// params renders a, b ...int; args a, b...; names a, b and types int,
+	// ...int.
+	"params": func(ps []TemplateParam) string {
+		return joinParams(ps, func(p TemplateParam) string { return p.Name + " " + p.Type })
+	},
+	"args": func(ps []TemplateParam) string {
+		return joinParams(ps, func(p TemplateParam) string {
+			if p.Variadic {
+				return p.Name + "..."
+			}
+			return p.Name
+		})
+	},
+	"names": func(ps []TemplateParam) string { return joinParams(ps, func(p TemplateParam) string { return p.Name }) },
+	"types": func(ps []TemplateParam) string { return joinParams(ps, func(p TemplateParam) string { return p.Type }) },
+}
+
+func init() {
+	for name, fn := range nameFuncs {
+		templateFuncs[name] = fn
+	}
+}
+
+func joinParams(params []TemplateParam, format func(TemplateParam) string) string {
+	var parts []string
+	for _, p := range params {
+		parts = append(parts, format(p))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseTemplate parses the template file at path. Like ParseNameTemplate, it
+// tries the template against sample data to report mistakes up front.
+func ParseTemplate(path string) (*template.Template, error) {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+
+	sample := TemplateData{
+		InterfaceName: "Interface",
+		MockName:      "Interface",
+		PackageName:   "mocks",
+		SourcePackage: "pkg",
+		SourcePath:    "example.com/pkg",
+		InterfaceRef:  "pkg.Interface",
+		Methods: []TemplateMethod{{
+			Name:      "Get",
+			Params:    []TemplateParam{{Name: "path", Type: "string"}},
+			Results:   []TemplateParam{{Name: "r0", Type: "error", Nillable: true}},
+			Signature: "(path string) error",
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, sample); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// templateText returns the normalized source of tmpl and the templates it
+// defines, or an empty string if there is no template.
+func templateText(tmpl *template.Template) string {
+	if tmpl == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			texts = append(texts, t.Name()+"="+t.Tree.Root.String())
+		}
+	}
+	sort.Strings(texts)
+
+	return strings.Join(texts, "\n")
+}
+
+func (g *Generator) generateTemplate() error {
+	return g.Template.Execute(&g.buf, g.templateData())
+}
+
+func (g *Generator) templateData() *TemplateData {
+	data := &TemplateData{
+		InterfaceName: g.iface.Name,
+		MockName:      g.mockName(),
+		PackageName:   g.pkg,
+		InPackage:     g.ip,
+		InterfaceRef:  g.ifaceRef(),
+	}
+
+	if g.ip {
+		data.PackageName = g.iface.File.Name.Name
+	}
+	if g.iface.Pkg != nil {
+		data.SourcePackage = g.iface.Pkg.Name()
+	}
+	if path, err := importPath(g.iface); err == nil {
+		data.SourcePath = filepath.ToSlash(path)
+	}
+
+	for _, imp := range g.iface.File.Imports {
+		ti := TemplateImport{}
+		ti.Path, _ = strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil {
+			ti.Name = imp.Name.Name
+		}
+		data.Imports = append(data.Imports, ti)
+	}
+
+	spec, docs := g.interfaceDocs()
+	data.Doc = spec
+
+	for idx, m := range g.methods() {
+		sig := g.iface.Type.Method(idx).Type().(*types.Signature)
+
+		tm := TemplateMethod{
+			Name:      m.Name,
+			Doc:       docs[m.Name],
+			Variadic:  m.Variadic,
+			Signature: m.signature(),
+		}
+		for i, name := range m.Params.Names {
+			tm.Params = append(tm.Params, TemplateParam{
+				Name:     name,
+				Type:     m.Params.Types[i],
+				Nillable: m.Params.Nilable[i] && !isArray(sig.Params().At(i).Type()),
+				Variadic: strings.HasPrefix(m.Params.Types[i], "..."),
+			})
+		}
+		for i, typ := range m.Returns.Types {
+			tm.Results = append(tm.Results, TemplateParam{
+				Name:     "r" + strconv.Itoa(i),
+				Type:     typ,
+				Nillable: m.Returns.Nilable[i] && !isArray(sig.Results().At(i).Type()),
+			})
+		}
+		data.Methods = append(data.Methods, tm)
+	}
+
+	return data
+}
+
+// interfaceDocs returns the doc comment of the interface and those of its
+// methods, by name, from the file declaring it.
+func (g *Generator) interfaceDocs() (string, map[string]string) {
+	docs := map[string]string{}
+
+	for _, decl := range g.iface.File.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != g.iface.Name {
+				continue
+			}
+
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				for _, field := range it.Methods.List {
+					for _, name := range field.Names {
+						docs[name.Name] = field.Doc.Text()
+					}
+				}
+			}
+
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			return doc.Text(), docs
+		}
+	}
+
+	return "", docs
+}
@@ -0,0 +1,172 @@
+package mockery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, text string) string {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "gen.tmpl")
+	require.NoError(t, ioutil.WriteFile(path, []byte(text), 0644))
+	return path
+}
+
+func TestGeneratorTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(writeTemplate(t, `type {{.MockName}}Counter struct {
+{{- range .Methods}}
+	{{.Name | lowerFirst}} int
+{{- end}}
+}
+{{range .Methods}}
+func (c *{{$.MockName}}Counter) {{.Name}}{{.Signature}} {
+	c.{{.Name | lowerFirst}}++
+	panic({{quote .Name}})
+}
+{{end}}`))
+	require.NoError(t, err)
+
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Template = tmpl
+
+	assert.NoError(t, gen.Generate())
+
+	expected := `type RequesterCounter struct {
+	get int
+}
+
+func (c *RequesterCounter) Get(path string) (string, error) {
+	c.get++
+	panic("Get")
+}
+`
+
+	assert.Equal(t, expected, gen.buf.String())
+}
+
+func TestGeneratorTemplateData(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_doc.go")))
+
+	iface, err := parser.Find("RequesterDoc")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	data := gen.templateData()
+
+	assert.Equal(t, "RequesterDoc", data.InterfaceName)
+	assert.Equal(t, "RequesterDoc", data.MockName)
+	assert.Equal(t, pkg, data.PackageName)
+	assert.Equal(t, "test", data.SourcePackage)
+	assert.Equal(t, "RequesterDoc fetches documents.\n", data.Doc)
+	assert.Equal(t, []TemplateImport{{Path: "io"}}, data.Imports)
+
+	require.Len(t, data.Methods, 2)
+
+	assert.Equal(t, "Close", data.Methods[0].Name)
+	assert.Empty(t, data.Methods[0].Params)
+	assert.Empty(t, data.Methods[0].Results)
+
+	get := data.Methods[1]
+	assert.Equal(t, "Get", get.Name)
+	assert.Equal(t, "Get returns the document at path.\n", get.Doc)
+	assert.Equal(t, "(path string) (io.Reader, error)", get.Signature)
+	assert.Equal(t, []TemplateParam{{Name: "path", Type: "string"}}, get.Params)
+	assert.Equal(t, []TemplateParam{
+		{Name: "r0", Type: "io.Reader", Nillable: true},
+		{Name: "r1", Type: "error", Nillable: true},
+	}, get.Results)
+}
+
+func TestGeneratorTemplateDataKeepsArgumentNames(t *testing.T) {
+	tmpl, err := ParseTemplate(writeTemplate(t, "{{range .Methods}}{{params .Params}};{{end}}"))
+	require.NoError(t, err)
+
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "shadowing.go")))
+
+	iface, err := parser.Find("Buffer")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Template = tmpl
+
+	assert.NoError(t, gen.Generate())
+	assert.Equal(t, "fmt string, args ...interface{};len int;", gen.buf.String())
+}
+
+func TestGeneratorTemplateDataArray(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_array.go")))
+
+	iface, err := parser.Find("RequesterArray")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	data := gen.templateData()
+
+	require.Len(t, data.Methods, 1)
+	assert.Equal(t, []TemplateParam{
+		{Name: "r0", Type: "[2]string"},
+		{Name: "r1", Type: "error", Nillable: true},
+	}, data.Methods[0].Results)
+}
+
+func TestGeneratorTemplateVariadic(t *testing.T) {
+	tmpl, err := ParseTemplate(writeTemplate(t, `{{range .Methods}}{{params .Params}}|{{args .Params}}|{{names .Params}}|{{types .Params}}|{{.Variadic}}{{end}}`))
+	require.NoError(t, err)
+
+	parser := NewParser()
+	require.NoError(t, parser.Parse(filepath.Join(fixturePath, "requester_variable.go")))
+
+	iface, err := parser.Find("RequesterVariable")
+	require.NoError(t, err)
+
+	gen := NewGenerator(iface, pkg)
+	gen.Template = tmpl
+
+	assert.NoError(t, gen.Generate())
+	assert.Equal(t, "values ...string|values...|values|...string|true", gen.buf.String())
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	_, err := ParseTemplate(filepath.Join(fixturePath, "missing.tmpl"))
+	assert.Error(t, err)
+
+	_, err = ParseTemplate(writeTemplate(t, "{{.MockName"))
+	assert.Error(t, err)
+
+	_, err = ParseTemplate(writeTemplate(t, "{{.Interface}}"))
+	assert.Error(t, err)
+
+	_, err = ParseTemplate(writeTemplate(t, "{{range .Methods}}{{.Name | camel}}{{end}}"))
+	assert.Error(t, err)
+}
+
+func TestTemplateText(t *testing.T) {
+	assert.Equal(t, "", templateText(nil))
+
+	a, err := ParseTemplate(writeTemplate(t, "{{.MockName}}"))
+	require.NoError(t, err)
+	b, err := ParseTemplate(writeTemplate(t, "{{ .MockName }}"))
+	require.NoError(t, err)
+	c, err := ParseTemplate(writeTemplate(t, "{{.InterfaceName}}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, templateText(a), templateText(b))
+	assert.NotEqual(t, templateText(a), templateText(c))
+}
@@ -0,0 +1,4 @@
+package mockery
+
+// SemVer is the version of mockery recorded in the header of generated files.
+const SemVer = "1.0.0"
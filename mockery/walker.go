@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
+	"time"
 )
 
 type Walker struct {
@@ -15,6 +16,11 @@ type Walker struct {
 	Recursive bool
 	Filter    *regexp.Regexp
 	LimitOne  bool
+	Log       *Logger
+
+	err     error
+	skipped []string
+	dirs    []string
 }
 
 type WalkerVisitor interface {
@@ -22,15 +28,43 @@ type WalkerVisitor interface {
 }
 
 func (this *Walker) Walk(visitor WalkerVisitor) (generated bool) {
+	this.err = nil
+	this.skipped = nil
+	this.dirs = nil
 	return this.doWalk(this.BaseDir, visitor)
 }
 
+// Err returns the error a visitor failed with during the last Walk, which
+// stops walking.
+func (this *Walker) Err() error {
+	return this.err
+}
+
+// Dirs returns the directories holding Go files that were searched during the
+// last Walk, whether or not they declare interfaces.
+func (this *Walker) Dirs() []string {
+	return this.dirs
+}
+
+// Skipped returns the files that failed to parse during the last Walk. The
+// interfaces of their packages were not visited.
+func (this *Walker) Skipped() []string {
+	return this.skipped
+}
+
 func (this *Walker) doWalk(dir string, visitor WalkerVisitor) (generated bool) {
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return
 	}
 
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".go") {
+			this.dirs = append(this.dirs, dir)
+			break
+		}
+	}
+
 	for _, file := range files {
 		if strings.HasPrefix(file.Name(), ".") {
 			continue
@@ -41,7 +75,7 @@ func (this *Walker) doWalk(dir string, visitor WalkerVisitor) (generated bool) {
 		if file.IsDir() {
 			if this.Recursive {
 				generated = this.doWalk(path, visitor) || generated
-				if generated && this.LimitOne {
+				if this.err != nil || generated && this.LimitOne {
 					return
 				}
 			}
@@ -54,19 +88,23 @@ func (this *Walker) doWalk(dir string, visitor WalkerVisitor) (generated bool) {
 
 		p := NewParser()
 
+		start := time.Now()
 		err = p.Parse(path)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error parsing file: ", err)
+			this.Log.Warn("Skipping file that failed to parse", "file", path, "error", err)
+			this.skipped = append(this.skipped, path)
 			continue
 		}
+		this.Log.Debug("Loaded package", "file", path, "duration", time.Since(start))
+
 		for _, iface := range p.Interfaces() {
 			if !this.Filter.MatchString(iface.Name) {
 				continue
 			}
 			err := visitor.VisitWalk(iface)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error walking %s: %s\n", iface.Name, err)
-				os.Exit(1)
+				this.err = fmt.Errorf("unable to walk interface %s: %s", iface.Name, err)
+				return
 			}
 			generated = true
 			if this.LimitOne {
@@ -79,36 +117,90 @@ func (this *Walker) doWalk(dir string, visitor WalkerVisitor) (generated bool) {
 }
 
 type GeneratorVisitor struct {
-	InPackage bool
-	Note      string
-	Osp       OutputStreamProvider
+	InPackage        bool
+	Note             string
+	Force            bool
+	MockNameTemplate *template.Template
+	Osp              OutputStreamProvider
+	Log              *Logger
+
+	// OutPackage names the package of out-of-package mocks, "mocks" if empty.
+	OutPackage string
+	// KeepTree gives the out-of-package mocks of each source package their
+	// own package, named after the source package, instead of OutPackage.
+	KeepTree bool
+	// WithExpecter generates typed expectation helpers in each mock.
+	WithExpecter bool
+	// WithConstructor generates a constructor asserting expectations when
+	// the test finishes in each mock.
+	WithConstructor bool
+	// WithAssertion generates a compile-time check that each mock implements
+	// its interface.
+	WithAssertion bool
+	// UnrollVariadic passes variadic arguments to Called one by one.
+	UnrollVariadic bool
+	// Backend selects the kind of mock generated, see Generator.Backend.
+	Backend string
+	// Template generates code from a user-supplied template instead of the
+	// backend, see Generator.Template.
+	Template *template.Template
+
+	// Generated and Unchanged record the names of the interfaces visited,
+	// depending on whether their mock was written or was up to date. With a
+	// CheckingOutputStreamProvider nothing is written and the interfaces are
+	// recorded in Checked instead.
+	Generated []string
+	Unchanged []string
+	Checked   []string
 }
 
 func (this *GeneratorVisitor) VisitWalk(iface *Interface) error {
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Unable to generated mock for '%s': %s\n", iface.Name, r)
+			this.Log.Error("Unable to generate mock", "interface", iface.Name, "error", fmt.Sprint(r))
 			return
 		}
 	}()
 
+	start := time.Now()
+
 	var out io.Writer
 	var pkg string
 
 	if this.InPackage {
 		pkg = iface.File.Name.String()
+	} else if this.KeepTree {
+		pkg = iface.Pkg.Name() + DefaultOutPackage
+	} else if this.OutPackage != "" {
+		pkg = this.OutPackage
 	} else {
-		pkg = "mocks"
+		pkg = DefaultOutPackage
+	}
+
+	fingerprint := Fingerprint(iface, this.fingerprintOptions(pkg)...)
+
+	if checker, ok := this.Osp.(UpToDateChecker); ok && !this.Force && checker.UpToDate(iface, fingerprint) {
+		this.Log.Info("Unchanged mock", "interface", iface.Name)
+		this.Unchanged = append(this.Unchanged, iface.Name)
+		return nil
 	}
 
 	out, err, closer := this.Osp.GetWriter(iface, pkg)
 	if err != nil {
-		fmt.Printf("Unable to get writer for %s: %s", iface.Name, err)
-		os.Exit(1)
+		return err
 	}
 	defer closer()
 
 	gen := NewGenerator(iface, pkg)
+	gen.MockNameTemplate = this.MockNameTemplate
+	gen.WithExpecter = this.WithExpecter
+	gen.WithConstructor = this.WithConstructor
+	gen.WithAssertion = this.WithAssertion
+	gen.UnrollVariadic = this.UnrollVariadic
+	gen.Backend = this.Backend
+	gen.Template = this.Template
+
+	gen.GenerateHeader(fingerprint)
 
 	if this.InPackage {
 		gen.GenerateIPPrologue()
@@ -127,5 +219,30 @@ func (this *GeneratorVisitor) VisitWalk(iface *Interface) error {
 	if err != nil {
 		return err
 	}
+
+	if _, ok := this.Osp.(*CheckingOutputStreamProvider); ok {
+		this.Log.Info("Checked mock", "interface", iface.Name, "duration", time.Since(start))
+		this.Checked = append(this.Checked, iface.Name)
+		return nil
+	}
+
+	this.Log.Info("Generated mock", "interface", iface.Name, "duration", time.Since(start))
+	this.Generated = append(this.Generated, iface.Name)
 	return nil
 }
+
+// fingerprintOptions lists the options that change the generated mock.
+func (this *GeneratorVisitor) fingerprintOptions(pkg string) []string {
+	return []string{
+		fmt.Sprintf("inpkg=%t", this.InPackage),
+		"pkg=" + pkg,
+		"note=" + this.Note,
+		"mockname=" + nameTemplateText(this.MockNameTemplate),
+		fmt.Sprintf("expecter=%t", this.WithExpecter),
+		fmt.Sprintf("constructor=%t", this.WithConstructor),
+		fmt.Sprintf("assertion=%t", this.WithAssertion),
+		fmt.Sprintf("unroll-variadic=%t", this.UnrollVariadic),
+		"backend=" + this.Backend,
+		"template=" + templateText(this.Template),
+	}
+}
@@ -1,12 +1,15 @@
 package mockery
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"path"
 	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type GatheringVisitor struct {
@@ -63,3 +66,28 @@ func TestWalkerRegexp(t *testing.T) {
 	assert.Equal(t, "AsyncProducer", first.Name)
 	assert.Equal(t, path.Join(wd, "fixtures/async.go"), first.Path)
 }
+
+type recordingStreamProvider struct {
+	pkg string
+	buf bytes.Buffer
+}
+
+func (this *recordingStreamProvider) GetWriter(iface *Interface, pkg string) (io.Writer, error, Cleanup) {
+	this.pkg = pkg
+	return &this.buf, nil, func() error { return nil }
+}
+
+func TestGeneratorVisitorKeepTreePackage(t *testing.T) {
+	parser := NewParser()
+	require.NoError(t, parser.Parse(testFile))
+
+	iface, err := parser.Find("Requester")
+	require.NoError(t, err)
+
+	osp := &recordingStreamProvider{}
+	visitor := &GeneratorVisitor{KeepTree: true, OutPackage: "mocks", Osp: osp}
+
+	assert.NoError(t, visitor.VisitWalk(iface))
+	assert.Equal(t, "testmocks", osp.pkg)
+	assert.Contains(t, osp.buf.String(), "package testmocks\n")
+}
@@ -0,0 +1,200 @@
+package mockery
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	defaultWatchDebounce = 300 * time.Millisecond
+)
+
+// Watcher polls the directories searched by Walker and walks again each
+// directory whose Go files changed, so mocks are regenerated as interfaces
+// are edited. Bursts of changes are coalesced until no change has been seen
+// for Debounce.
+type Watcher struct {
+	Walker   Walker
+	Interval time.Duration
+	Debounce time.Duration
+	// Ignore lists directories that are not watched, such as the output
+	// directory of the mocks.
+	Ignore []string
+
+	// headers caches whether each file scanned carries mockery's generated
+	// header, until the file is modified.
+	headers map[string]scannedFile
+}
+
+type scannedFile struct {
+	modTime   time.Time
+	generated bool
+}
+
+// Watch blocks, regenerating mocks through visitor, until ctx is done.
+func (this *Watcher) Watch(ctx context.Context, visitor WalkerVisitor) {
+	interval := this.Interval
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+
+	debounce := this.Debounce
+	if debounce == 0 {
+		debounce = defaultWatchDebounce
+	}
+
+	current := this.scan()
+
+	for {
+		if !sleep(ctx, interval) {
+			return
+		}
+
+		next := this.scan()
+		changed := changedDirs(current, next)
+		current = next
+
+		if len(changed) == 0 {
+			continue
+		}
+
+		for {
+			if !sleep(ctx, debounce) {
+				return
+			}
+
+			next = this.scan()
+			more := changedDirs(current, next)
+			current = next
+
+			if len(more) == 0 {
+				break
+			}
+			for dir := range more {
+				changed[dir] = true
+			}
+		}
+
+		dirs := make([]string, 0, len(changed))
+		for dir := range changed {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+
+		for _, dir := range dirs {
+			walker := this.Walker
+			walker.BaseDir = dir
+			walker.Recursive = false
+
+			start := time.Now()
+			generated := walker.Walk(visitor)
+			if err := walker.Err(); err != nil {
+				this.Walker.Log.Error("Unable to regenerate mocks", "dir", dir, "error", err)
+			} else if generated {
+				this.Walker.Log.Info("Regenerated mocks", "dir", dir, "duration", time.Since(start))
+			}
+		}
+	}
+}
+
+// sleep waits for d and returns false if ctx was done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// scan returns the modification time of every Go file the Walker would parse,
+// except the mocks mockery generated. Those are rewritten by every walk, and
+// with -inpkg they sit next to the sources.
+func (this *Watcher) scan() map[string]time.Time {
+	files := make(map[string]time.Time)
+	this.scanDir(this.Walker.BaseDir, files)
+	return files
+}
+
+func (this *Watcher) scanDir(dir string, files map[string]time.Time) {
+	for _, ignore := range this.Ignore {
+		if samePath(ignore, dir) {
+			return
+		}
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if this.Walker.Recursive {
+				this.scanDir(path, files)
+			}
+			continue
+		}
+
+		if strings.HasSuffix(path, ".go") && !this.generated(path, entry.ModTime()) {
+			files[path] = entry.ModTime()
+		}
+	}
+}
+
+// generated reports whether the file at path, last modified at modTime,
+// carries mockery's generated header.
+func (this *Watcher) generated(path string, modTime time.Time) bool {
+	if f, ok := this.headers[path]; ok && f.modTime.Equal(modTime) {
+		return f.generated
+	}
+
+	generated, _ := isGenerated(path)
+
+	if this.headers == nil {
+		this.headers = make(map[string]scannedFile)
+	}
+	this.headers[path] = scannedFile{modTime: modTime, generated: generated}
+
+	return generated
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// changedDirs returns the directories containing files that were added,
+// removed or modified between two scans.
+func changedDirs(before, after map[string]time.Time) map[string]bool {
+	dirs := make(map[string]bool)
+
+	for path, mtime := range after {
+		if prev, ok := before[path]; !ok || !prev.Equal(mtime) {
+			dirs[filepath.Dir(path)] = true
+		}
+	}
+
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			dirs[filepath.Dir(path)] = true
+		}
+	}
+
+	return dirs
+}
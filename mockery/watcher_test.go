@@ -0,0 +1,188 @@
+package mockery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedDirs(t *testing.T) {
+	now := time.Now()
+
+	before := map[string]time.Time{
+		"a/same.go":    now,
+		"b/changed.go": now,
+		"c/removed.go": now,
+	}
+	after := map[string]time.Time{
+		"a/same.go":    now,
+		"b/changed.go": now.Add(time.Second),
+		"d/added.go":   now,
+	}
+
+	assert.Equal(t, map[string]bool{"b": true, "c": true, "d": true}, changedDirs(before, after))
+}
+
+func TestWatcherRegeneratesChangedDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("a.go", "package watched\n")
+
+	watcher := &Watcher{
+		Walker: Walker{
+			BaseDir: dir,
+			Filter:  regexp.MustCompile(".*"),
+		},
+		Interval: 10 * time.Millisecond,
+		Debounce: 10 * time.Millisecond,
+	}
+
+	visited := make(chan *Interface, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Watch(ctx, notifyingVisitor(visited))
+
+	time.Sleep(50 * time.Millisecond)
+	write("b.go", "package watched\n\ntype Watched interface {\n\tGet() error\n}\n")
+
+	select {
+	case iface := <-visited:
+		assert.Equal(t, "Watched", iface.Name)
+	case <-time.After(10 * time.Second):
+		t.Fatal("interface was not regenerated")
+	}
+}
+
+func TestWatcherIgnoresGeneratedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("a.go", "package watched\n\ntype Watched interface {\n\tGet() error\n}\n")
+	write("mock_Watched.go", generatedPrefix+" v"+SemVer+". DO NOT EDIT.\n\npackage watched\n")
+
+	watcher := &Watcher{
+		Walker: Walker{
+			BaseDir: dir,
+			Filter:  regexp.MustCompile(".*"),
+		},
+		Interval: 10 * time.Millisecond,
+		Debounce: 10 * time.Millisecond,
+	}
+
+	visited := make(chan *Interface, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Watch(ctx, notifyingVisitor(visited))
+
+	time.Sleep(50 * time.Millisecond)
+	write("mock_Watched.go", generatedPrefix+" v"+SemVer+". DO NOT EDIT.\n\npackage watched\n\n// changed\n")
+
+	select {
+	case iface := <-visited:
+		t.Fatalf("%s was regenerated after its mock changed", iface.Name)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestWatcherLogsFailedRegeneration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("a.go", "package watched\n")
+
+	var log bytes.Buffer
+	out := &syncWriter{w: &log}
+	watcher := &Watcher{
+		Walker: Walker{
+			BaseDir: dir,
+			Filter:  regexp.MustCompile(".*"),
+			Log:     &Logger{Out: out, Level: LogInfo},
+		},
+		Interval: 10 * time.Millisecond,
+		Debounce: 10 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watcher.Watch(ctx, failingVisitor{})
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	write("b.go", "package watched\n\ntype Watched interface {\n\tGet() error\n}\n")
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(out.String(), "Unable to regenerate mocks") {
+		if time.Now().After(deadline) {
+			t.Fatal("failed regeneration was not logged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+	assert.NotContains(t, log.String(), "Regenerated mocks")
+}
+
+type failingVisitor struct{}
+
+func (failingVisitor) VisitWalk(iface *Interface) error {
+	return errors.New("output is not writable")
+}
+
+// syncWriter guards a buffer written by the watcher and read by the test.
+type syncWriter struct {
+	mu sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (this *syncWriter) Write(p []byte) (int, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.w.Write(p)
+}
+
+func (this *syncWriter) String() string {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.w.String()
+}
+
+// notifyingVisitor sends each interface visited, dropping those visited while
+// the previous one has not been received.
+type notifyingVisitor chan *Interface
+
+func (this notifyingVisitor) VisitWalk(iface *Interface) error {
+	select {
+	case this <- iface:
+	default:
+	}
+	return nil
+}
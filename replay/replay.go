@@ -0,0 +1,228 @@
+// Package replay records the calls made to an implementation of an interface
+// and replays them later, for the recorders and replayers mockery generates
+// with -backend replay.
+//
+// Calls are stored one per line as JSON, with their method name, arguments and
+// results. Arguments and results must therefore be serializable with
+// encoding/json, except for errors which are stored as their message and
+// replayed as errors with the same message. The generated code leaves out
+// arguments of type context.Context, func and chan.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Call is a call read from or written to a recording.
+type Call struct {
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args"`
+	Results []json.RawMessage `json:"results"`
+}
+
+// recordedError stores an error result as its message.
+type recordedError struct {
+	Error string `json:"error"`
+}
+
+func encode(values []interface{}) ([]json.RawMessage, error) {
+	var encoded []json.RawMessage
+	for _, value := range values {
+		if err, ok := value.(error); ok {
+			value = recordedError{err.Error()}
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, data)
+	}
+	return encoded, nil
+}
+
+// Recorder appends calls to a recording. It is safe for concurrent use.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	err  error
+}
+
+// NewRecorder creates the recording at path, replacing any existing one.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{file: file}, nil
+}
+
+// Record appends a call of method with the given arguments and results. The
+// first error encountered stops the recording and is returned by Close.
+func (r *Recorder) Record(method string, args, results []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	call := Call{Method: method}
+	if call.Args, r.err = encode(args); r.err != nil {
+		r.err = fmt.Errorf("unable to record the arguments of %s: %s", method, r.err)
+		return
+	}
+	if call.Results, r.err = encode(results); r.err != nil {
+		r.err = fmt.Errorf("unable to record the results of %s: %s", method, r.err)
+		return
+	}
+
+	r.err = json.NewEncoder(r.file).Encode(call)
+}
+
+// Close closes the recording and returns the first error met while recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.file.Close(); r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// TestingT is the part of *testing.T a Player reports failures to.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+// Player answers calls with the results of a recording, in the order they were
+// recorded. It is safe for concurrent use.
+type Player struct {
+	t     TestingT
+	mu    sync.Mutex
+	calls []Call
+	next  int
+}
+
+// NewPlayer loads the recording at path. Failing to load it, and any call that
+// diverges from the recording, fail t.
+func NewPlayer(t TestingT, path string) *Player {
+	p := &Player{t: t}
+
+	file, err := os.Open(path)
+	if err != nil {
+		p.fail("unable to load recording: %s", err)
+		return p
+	}
+	defer file.Close()
+
+	dec := json.NewDecoder(file)
+	for {
+		var call Call
+		if err := dec.Decode(&call); err == io.EOF {
+			break
+		} else if err != nil {
+			p.fail("unable to read call %d of %s: %s", len(p.calls), path, err)
+			return p
+		}
+		p.calls = append(p.calls, call)
+	}
+
+	return p
+}
+
+func (p *Player) fail(format string, args ...interface{}) {
+	p.t.Errorf("replay: "+format, args...)
+	p.t.FailNow()
+}
+
+// Play checks that the next recorded call is a call of method with the same
+// arguments and decodes its results into the pointers in results.
+func (p *Player) Play(method string, args []interface{}, results ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.next == len(p.calls) {
+		p.fail("unexpected call of %s after the %d recorded calls", method, len(p.calls))
+		return
+	}
+
+	call := p.calls[p.next]
+	p.next++
+
+	if call.Method != method {
+		p.fail("call %d is %s but %s was recorded", p.next-1, method, call.Method)
+		return
+	}
+
+	encoded, err := encode(args)
+	if err != nil {
+		p.fail("unable to compare the arguments of %s: %s", method, err)
+		return
+	}
+	if !equal(encoded, call.Args) {
+		p.fail("call %d of %s has arguments %s but %s were recorded", p.next-1, method, join(encoded), join(call.Args))
+		return
+	}
+	if len(call.Results) != len(results) {
+		p.fail("call %d of %s has %d results but %d were recorded", p.next-1, method, len(results), len(call.Results))
+		return
+	}
+
+	for i, result := range results {
+		if err := decode(call.Results[i], result); err != nil {
+			p.fail("unable to decode result %d of %s: %s", i, method, err)
+			return
+		}
+	}
+}
+
+func decode(data json.RawMessage, result interface{}) error {
+	target, ok := result.(*error)
+	if !ok {
+		return json.Unmarshal(data, result)
+	}
+
+	var recorded *recordedError
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return err
+	}
+	if recorded != nil {
+		*target = errors.New(recorded.Error)
+	}
+	return nil
+}
+
+func equal(a, b []json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func join(values []json.RawMessage) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// Remaining returns the number of recorded calls not played yet.
+func (p *Player) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.calls) - p.next
+}
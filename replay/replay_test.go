@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records the failures of a Player.
+type fakeT struct {
+	failures []string
+}
+
+func (this *fakeT) Errorf(format string, args ...interface{}) {
+	this.failures = append(this.failures, fmt.Sprintf(format, args...))
+}
+
+func (this *fakeT) FailNow() {}
+
+func record(t *testing.T, calls func(r *Recorder)) string {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "calls.json")
+
+	r, err := NewRecorder(path)
+	require.NoError(t, err)
+	calls(r)
+	require.NoError(t, r.Close())
+
+	return path
+}
+
+func TestReplay(t *testing.T) {
+	path := record(t, func(r *Recorder) {
+		r.Record("Get", []interface{}{"a"}, []interface{}{"x", nil})
+		r.Record("Get", []interface{}{"b"}, []interface{}{"", errors.New("not found")})
+	})
+
+	ft := &fakeT{}
+	p := NewPlayer(ft, path)
+	assert.Equal(t, 2, p.Remaining())
+
+	var value string
+	var err error
+	p.Play("Get", []interface{}{"a"}, &value, &err)
+	assert.Equal(t, "x", value)
+	assert.NoError(t, err)
+
+	p.Play("Get", []interface{}{"b"}, &value, &err)
+	assert.Equal(t, "", value)
+	assert.EqualError(t, err, "not found")
+
+	assert.Empty(t, ft.failures)
+	assert.Equal(t, 0, p.Remaining())
+}
+
+func TestReplayDivergence(t *testing.T) {
+	path := record(t, func(r *Recorder) {
+		r.Record("Get", []interface{}{"a"}, []interface{}{"x", nil})
+	})
+
+	var value string
+	var err error
+
+	ft := &fakeT{}
+	NewPlayer(ft, path).Play("Get", []interface{}{"b"}, &value, &err)
+	assert.Equal(t, []string{`replay: call 0 of Get has arguments ["b"] but ["a"] were recorded`}, ft.failures)
+
+	ft = &fakeT{}
+	NewPlayer(ft, path).Play("Put", []interface{}{"a"})
+	assert.Equal(t, []string{"replay: call 0 is Put but Get was recorded"}, ft.failures)
+
+	ft = &fakeT{}
+	p := NewPlayer(ft, path)
+	p.Play("Get", []interface{}{"a"}, &value, &err)
+	p.Play("Get", []interface{}{"a"}, &value, &err)
+	assert.Equal(t, []string{"replay: unexpected call of Get after the 1 recorded calls"}, ft.failures)
+}
+
+func TestRecorderRejectsUnserializableValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r, err := NewRecorder(filepath.Join(dir, "calls.json"))
+	require.NoError(t, err)
+
+	r.Record("Subscribe", []interface{}{make(chan int)}, nil)
+	assert.Error(t, r.Close())
+}
+
+// Getter is mocked by GetterRecorder and GetterReplayer, written the way
+// mockery generates them with -backend replay: the context is passed on but
+// neither recorded nor compared.
+type Getter interface {
+	Get(ctx context.Context, path string) (string, error)
+}
+
+type GetterRecorder struct {
+	Inner    Getter
+	Recorder *Recorder
+}
+
+func (_m *GetterRecorder) Get(ctx context.Context, path string) (string, error) {
+	r0, r1 := _m.Inner.Get(ctx, path)
+	_m.Recorder.Record("Get", []interface{}{path}, []interface{}{r0, r1})
+	return r0, r1
+}
+
+type GetterReplayer struct {
+	Player *Player
+}
+
+func (_m *GetterReplayer) Get(ctx context.Context, path string) (string, error) {
+	var r0 string
+	var r1 error
+	_m.Player.Play("Get", []interface{}{path}, &r0, &r1)
+	return r0, r1
+}
+
+type pathGetter struct{}
+
+func (pathGetter) Get(ctx context.Context, path string) (string, error) {
+	return "contents of " + path, ctx.Err()
+}
+
+func TestReplayContextArgument(t *testing.T) {
+	path := record(t, func(r *Recorder) {
+		g := &GetterRecorder{Inner: pathGetter{}, Recorder: r}
+		g.Get(context.Background(), "a")
+	})
+
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+	defer cancel()
+
+	ft := &fakeT{}
+	g := &GetterReplayer{Player: NewPlayer(ft, path)}
+
+	value, err := g.Get(ctx, "a")
+	assert.Empty(t, ft.failures)
+	assert.Equal(t, "contents of a", value)
+	assert.NoError(t, err)
+}